@@ -42,12 +42,43 @@ func main() {
 
 	retryClient := client.NewRetryDecorator(rawClient, retryCfg)
 
-	// Parser and chorus detector used by the service
-	parser := service.NewParser()
-	chorusDetector := service.NewChorusDetector()
+	// Parser and analyzers used by the service
+	parser := service.NewParserWithConfig(service.ParserConfig{
+		Limits: service.ParseLimits{
+			MaxLineLength: cfg.ParseMaxLineLength,
+			MaxLines:      cfg.ParseMaxLines,
+			MaxBytes:      cfg.ParseMaxBytes,
+		},
+	})
+	chorusDetector := service.NewChorusDetectorWithThreshold(cfg.ChorusSimilarityThreshold)
+	statsCalc := service.NewStatisticsCalculator()
+
+	// Extra explicit words extend the bundled lists
+	wordLists, err := service.LoadWordLists(cfg.ProfanityListsDir)
+	if err != nil {
+		log.Fatalf("failed to load profanity word lists: %v", err)
+	}
+
+	// Junk line rules extend the built-in ones
+	cleanRules, err := service.LoadCleanRules(cfg.CleanRulesFile)
+	if err != nil {
+		log.Fatalf("failed to load clean rules: %v", err)
+	}
+	cleaner, err := service.NewLineCleanerWithRules(cleanRules)
+	if err != nil {
+		log.Fatalf("failed to load clean rules: %v", err)
+	}
 
 	// Service
-	svc := service.NewLyricsService(retryClient, parser, chorusDetector)
+	svc := service.NewLyricsServiceWithCleaner(retryClient, parser, cleaner,
+		service.NewStructureAnalyzer(chorusDetector),
+		service.NewStatisticsAnalyzer(statsCalc),
+		service.NewRhymeAnalyzer(),
+		service.NewFlowAnalyzer(),
+		service.NewVocabularyAnalyzer(),
+		service.NewSentimentAnalyzer(),
+		service.NewProfanityAnalyzerWithLists(wordLists),
+	)
 
 	// Build router and server
 	r := server.NewRouter(svc)
@@ -15,6 +15,16 @@ type Config struct {
 	RetryBackoff    time.Duration
 	RetryMaxBackoff time.Duration
 	RetryMultiplier float64
+
+	ParseMaxLineLength int
+	ParseMaxLines      int
+	ParseMaxBytes      int64
+
+	ChorusSimilarityThreshold float64
+
+	ProfanityListsDir string
+
+	CleanRulesFile string
 }
 
 // Load reads configuration from environment variables with sensible defaults
@@ -27,6 +37,16 @@ func Load() (*Config, error) {
 		RetryBackoff:    parseDurationOrDefault(getEnv("RETRY_BACKOFF", "100ms"), 100*time.Millisecond),
 		RetryMaxBackoff: parseDurationOrDefault(getEnv("RETRY_MAX_BACKOFF", "5s"), 5*time.Second),
 		RetryMultiplier: parseFloatOrDefault(getEnv("RETRY_MULTIPLIER", "2.0"), 2.0),
+
+		ParseMaxLineLength: parseIntOrDefault(getEnv("PARSE_MAX_LINE_LENGTH", "4096"), 4096),
+		ParseMaxLines:      parseIntOrDefault(getEnv("PARSE_MAX_LINES", "10000"), 10000),
+		ParseMaxBytes:      int64(parseIntOrDefault(getEnv("PARSE_MAX_BYTES", "1048576"), 1<<20)),
+
+		ChorusSimilarityThreshold: parseFloatOrDefault(getEnv("CHORUS_SIMILARITY_THRESHOLD", "0.9"), 0.9),
+
+		ProfanityListsDir: getEnv("PROFANITY_LISTS_DIR", ""),
+
+		CleanRulesFile: getEnv("CLEAN_RULES_FILE", ""),
 	}
 
 	return cfg, nil
@@ -0,0 +1,112 @@
+// Package export renders analyzed lyrics into file formats used by players:
+// LRC, SRT, WebVTT and Apple-style TTML.
+package export
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// defaultCueDurationMs is used for a final line whose end time is unknown
+const defaultCueDurationMs = 4000
+
+// Sentinel errors for export failures
+var (
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrNotSynced         = errors.New("lyrics have no timestamps to export")
+)
+
+// Exporter renders lyrics into a specific file format
+type Exporter interface {
+	// Export renders the track's lyrics into the target format
+	Export(track model.Track, lyrics *model.LyricsData) ([]byte, error)
+
+	// ContentType returns the HTTP Content-Type for the rendered output
+	ContentType() string
+
+	// FileExtension returns the file extension without the leading dot
+	FileExtension() string
+}
+
+// New returns the exporter for the given format (lrc, srt, vtt or ttml)
+func New(format string) (Exporter, error) {
+	switch format {
+	case model.FormatLRC:
+		return &LRCExporter{}, nil
+	case model.FormatSRT:
+		return &SRTExporter{}, nil
+	case model.FormatWebVTT:
+		return &WebVTTExporter{}, nil
+	case model.FormatTTML:
+		return &TTMLExporter{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// cue is a lyric line with resolved start and end times
+type cue struct {
+	line    model.LyricLine
+	startMs int
+	endMs   int
+}
+
+// buildCues resolves timings for every timed line, including breaks.
+// Returns ErrNotSynced when the lyrics carry no timestamps.
+func buildCues(lyrics *model.LyricsData) ([]cue, error) {
+	if lyrics == nil || !lyrics.HasTimestamps {
+		return nil, ErrNotSynced
+	}
+
+	var cues []cue
+	for _, line := range lyrics.Lines {
+		if line.StartMs == nil {
+			continue
+		}
+
+		start := *line.StartMs
+		end := start + defaultCueDurationMs
+		if line.EndMs != nil {
+			end = *line.EndMs
+		}
+
+		cues = append(cues, cue{line: line, startMs: start, endMs: end})
+	}
+
+	if len(cues) == 0 {
+		return nil, ErrNotSynced
+	}
+
+	return cues, nil
+}
+
+// timestampFormat describes how a file format writes a time
+type timestampFormat struct {
+	hours        bool   // write hours separately instead of folding them into minutes
+	fraction     string // separator before the fraction of a second
+	centiseconds bool   // round to centiseconds instead of writing milliseconds
+}
+
+// Timestamp formats used by the exporters
+var (
+	lrcTime = timestampFormat{fraction: ".", centiseconds: true} // mm:ss.xx
+	srtTime = timestampFormat{hours: true, fraction: ","}        // hh:mm:ss,mmm
+	cueTime = timestampFormat{hours: true, fraction: "."}        // hh:mm:ss.mmm for WebVTT and TTML
+)
+
+// format writes milliseconds in the timestamp format
+func (f timestampFormat) format(ms int) string {
+	fraction, width := ms%1000, 3
+	if f.centiseconds {
+		ms = (ms + 5) / 10 * 10
+		fraction, width = ms%1000/10, 2
+	}
+
+	seconds := ms / 1000
+	if !f.hours {
+		return fmt.Sprintf("%02d:%02d%s%0*d", seconds/60, seconds%60, f.fraction, width, fraction)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%0*d", seconds/3600, seconds/60%60, seconds%60, f.fraction, width, fraction)
+}
@@ -0,0 +1,359 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func testTrack() model.Track {
+	return model.Track{Name: "Song", Artist: "Artist", Album: "Album", Duration: 213}
+}
+
+func testLyrics() *model.LyricsData {
+	return &model.LyricsData{
+		Type:          model.LyricsTypeSynced,
+		HasTimestamps: true,
+		Lines: []model.LyricLine{
+			{LineNumber: 1, Timestamp: strPtr("00:12.00"), StartMs: intPtr(12000), EndMs: intPtr(15500), Text: "First & best"},
+			{LineNumber: 2, Timestamp: strPtr("00:15.50"), StartMs: intPtr(15500), EndMs: intPtr(20000), IsBreak: true},
+			{LineNumber: 3, Timestamp: strPtr("00:20.00"), StartMs: intPtr(20000), Text: "Last <line>"},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		format      string
+		contentType string
+		extension   string
+		wantError   bool
+	}{
+		{model.FormatLRC, "text/plain; charset=utf-8", "lrc", false},
+		{model.FormatSRT, "application/x-subrip; charset=utf-8", "srt", false},
+		{model.FormatWebVTT, "text/vtt; charset=utf-8", "vtt", false},
+		{model.FormatTTML, "application/ttml+xml; charset=utf-8", "ttml", false},
+		{"docx", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			exporter, err := New(tt.format)
+
+			if tt.wantError {
+				assert.ErrorIs(t, err, ErrUnsupportedFormat)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.contentType, exporter.ContentType())
+			assert.Equal(t, tt.extension, exporter.FileExtension())
+		})
+	}
+}
+
+func TestExporters_NotSynced(t *testing.T) {
+	plain := &model.LyricsData{
+		Type:  model.LyricsTypePlain,
+		Lines: []model.LyricLine{{LineNumber: 1, Text: "Plain"}},
+	}
+
+	for _, format := range []string{model.FormatLRC, model.FormatSRT, model.FormatWebVTT, model.FormatTTML} {
+		exporter, _ := New(format)
+
+		_, err := exporter.Export(testTrack(), plain)
+		assert.ErrorIs(t, err, ErrNotSynced, format)
+
+		_, err = exporter.Export(testTrack(), nil)
+		assert.ErrorIs(t, err, ErrNotSynced, format)
+	}
+}
+
+func TestLRCExporter_Export(t *testing.T) {
+	lyrics := testLyrics()
+	lyrics.Header = &model.LRCHeader{Title: "Header Title", Author: "someone"}
+	lyrics.Lines[0].Words = []model.Word{
+		{Text: "First", StartMs: 12000, EndMs: 12500},
+		{Text: "&", StartMs: 12500, EndMs: 12800},
+		{Text: "best", StartMs: 12800, EndMs: 13400},
+	}
+
+	out, err := (&LRCExporter{}).Export(testTrack(), lyrics)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `[ar:Artist]
+[ti:Header Title]
+[al:Album]
+[by:someone]
+[length:03:33]
+[00:12.00]<00:12.00>First <00:12.50>& <00:12.80>best<00:13.40>
+[00:15.50]
+[00:20.00]Last <line>
+`, string(out))
+}
+
+func TestLRCExporter_Export_SyllablesRoundTrip(t *testing.T) {
+	parser := service.NewParser()
+	source := "[00:01.00]<00:01.00>So <00:01.40>beau<00:01.80>ti<00:02.10>ful<00:02.60> to<00:03.00>night<00:03.50>\n"
+
+	lines, err := parser.ParseSyncedLyrics(source)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	require.Equal(t, "So beautiful tonight", lines[0].Text)
+
+	out, err := (&LRCExporter{}).Export(model.Track{}, &model.LyricsData{HasTimestamps: true, Lines: lines})
+	require.NoError(t, err)
+	assert.Equal(t, "[00:01.00]<00:01.00>So <00:01.40>beau<00:01.80>ti<00:02.10>ful <00:02.60>to<00:03.00>night<00:03.50>\n", string(out))
+
+	reparsed, err := parser.ParseSyncedLyrics(string(out))
+	require.NoError(t, err)
+	require.Len(t, reparsed, 1)
+	assert.Equal(t, lines[0].Text, reparsed[0].Text)
+	assert.Equal(t, lines[0].Words, reparsed[0].Words)
+}
+
+func TestWordSeparators(t *testing.T) {
+	words := func(texts ...string) []model.Word {
+		var result []model.Word
+		for _, text := range texts {
+			result = append(result, model.Word{Text: text})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		words []model.Word
+		want  []string
+	}{
+		{"separate words", "First & best", words("First", "&", "best"), []string{"", " ", " "}},
+		{"syllables", "beautiful day", words("beau", "ti", "ful", "day"), []string{"", "", "", " "}},
+		{"word missing from the text", "Hello", words("Hello", "there"), []string{"", " "}},
+		{"repeated syllables", "la la", words("la", "la"), []string{"", " "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, wordSeparators(tt.text, tt.words))
+		})
+	}
+}
+
+func TestLRCExporter_Export_Singers(t *testing.T) {
+	lyrics := testLyrics()
+	lyrics.Lines[0].Singer = "v1"
+	lyrics.Lines[2].Singer = "Elton John"
+
+	out, err := (&LRCExporter{}).Export(model.Track{}, lyrics)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[00:12.00]v1: First & best\n[00:15.50]\n[00:20.00](Elton John:) Last <line>\n", string(out))
+}
+
+func TestLRCExporter_Export_SingersRoundTrip(t *testing.T) {
+	parser := service.NewParser()
+	source := `[00:01.00](Jay:) Hello there
+[00:03.00]v2: Second line
+[00:05.00]F: Third line
+[00:07.00](Elton John:) Fourth line
+[00:09.00]No singer here`
+
+	lines, err := parser.ParseSyncedLyrics(source)
+	require.NoError(t, err)
+
+	out, err := (&LRCExporter{}).Export(model.Track{}, &model.LyricsData{HasTimestamps: true, Lines: lines})
+	require.NoError(t, err)
+
+	reparsed, err := parser.ParseSyncedLyrics(string(out))
+	require.NoError(t, err)
+	require.Len(t, reparsed, len(lines))
+	for i := range lines {
+		assert.Equal(t, lines[i].Singer, reparsed[i].Singer)
+		assert.Equal(t, lines[i].Text, reparsed[i].Text)
+	}
+	assert.Equal(t, "Jay", reparsed[0].Singer)
+	assert.Equal(t, "Hello there", reparsed[0].Text)
+}
+
+func TestSingerPrefix(t *testing.T) {
+	tests := []struct {
+		singer string
+		want   string
+	}{
+		{"", ""},
+		{"v1", "v1: "},
+		{"v12", "v12: "},
+		{"M", "M: "},
+		{"Jay", "(Jay:) "},
+		{"Ed", "(Ed:) "},
+		{"Artist (feat. X)", "(Artist feat. X:) "},
+		{"():", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.singer, func(t *testing.T) {
+			assert.Equal(t, tt.want, singerPrefix(tt.singer))
+		})
+	}
+}
+
+func TestSRTExporter_Export(t *testing.T) {
+	out, err := (&SRTExporter{}).Export(testTrack(), testLyrics())
+
+	assert.NoError(t, err)
+	// The break produces no cue and the open-ended last line gets a default duration
+	assert.Equal(t, `1
+00:00:12,000 --> 00:00:15,500
+First & best
+
+2
+00:00:20,000 --> 00:00:24,000
+Last <line>
+`, string(out))
+}
+
+func TestWebVTTExporter_Export(t *testing.T) {
+	out, err := (&WebVTTExporter{}).Export(testTrack(), testLyrics())
+
+	assert.NoError(t, err)
+	assert.Equal(t, `WEBVTT
+
+00:00:12.000 --> 00:00:15.500
+First &amp; best
+
+00:00:20.000 --> 00:00:24.000
+Last &lt;line&gt;
+`, string(out))
+}
+
+func TestSRTExporter_Export_SingersRoundTrip(t *testing.T) {
+	parser := service.NewParser()
+	source := `1
+00:00:01,000 --> 00:00:03,000
+(Jay:) Hello there
+
+2
+00:00:03,000 --> 00:00:05,000
+v2: Second line
+
+3
+00:00:05,000 --> 00:00:07,000
+No singer here
+`
+
+	lines, err := parser.ParseSRT(source)
+	require.NoError(t, err)
+
+	out, err := (&SRTExporter{}).Export(model.Track{}, &model.LyricsData{HasTimestamps: true, Lines: lines})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "(Jay:) Hello there")
+
+	reparsed, err := parser.ParseSRT(string(out))
+	require.NoError(t, err)
+	require.Len(t, reparsed, len(lines))
+	for i := range lines {
+		assert.Equal(t, lines[i].Singer, reparsed[i].Singer)
+		assert.Equal(t, lines[i].Text, reparsed[i].Text)
+	}
+	assert.Equal(t, "Jay", reparsed[0].Singer)
+	assert.Equal(t, "v2", reparsed[1].Singer)
+}
+
+func TestWebVTTExporter_Export_SingersRoundTrip(t *testing.T) {
+	parser := service.NewParser()
+	source := `WEBVTT
+
+00:00:01.000 --> 00:00:03.000
+<v Elton John>Hello &amp; welcome</v>
+
+00:00:03.000 --> 00:00:05.000
+No singer here
+`
+
+	lines, err := parser.ParseWebVTT(source)
+	require.NoError(t, err)
+
+	out, err := (&WebVTTExporter{}).Export(model.Track{}, &model.LyricsData{HasTimestamps: true, Lines: lines})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "<v Elton John>Hello &amp; welcome\n")
+
+	reparsed, err := parser.ParseWebVTT(string(out))
+	require.NoError(t, err)
+	require.Len(t, reparsed, len(lines))
+	for i := range lines {
+		assert.Equal(t, lines[i].Singer, reparsed[i].Singer)
+		assert.Equal(t, lines[i].Text, reparsed[i].Text)
+	}
+	assert.Equal(t, "Elton John", reparsed[0].Singer)
+}
+
+func TestTTMLExporter_Export(t *testing.T) {
+	out, err := (&TTMLExporter{}).Export(testTrack(), testLyrics())
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:itunes="http://music.apple.com/lyric-ttml-internal" xmlns:ttm="http://www.w3.org/ns/ttml#metadata" itunes:timing="Line">
+  <head>
+    <metadata>
+      <ttm:title>Song</ttm:title>
+      <ttm:agent type="person"><ttm:name>Artist</ttm:name></ttm:agent>
+    </metadata>
+  </head>
+  <body dur="00:03:33.000">
+    <div begin="00:00:12.000" end="00:00:15.500">
+      <p begin="00:00:12.000" end="00:00:15.500">First &amp; best</p>
+    </div>
+    <div begin="00:00:20.000" end="00:00:24.000">
+      <p begin="00:00:20.000" end="00:00:24.000">Last &lt;line&gt;</p>
+    </div>
+  </body>
+</tt>
+`, string(out))
+}
+
+func TestTTMLExporter_Export_WordTiming(t *testing.T) {
+	lyrics := testLyrics()
+	lyrics.Lines[2].Words = []model.Word{
+		{Text: "Last", StartMs: 20000, EndMs: 20400},
+		{Text: "word", StartMs: 20400},
+	}
+
+	out, err := (&TTMLExporter{}).Export(testTrack(), lyrics)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `itunes:timing="Word"`)
+	assert.Contains(t, string(out), `<span begin="00:00:20.000" end="00:00:20.400">Last</span> <span begin="00:00:20.400" end="00:00:24.000">word</span>`)
+}
+
+func TestTimestampFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format timestampFormat
+		ms     int
+		want   string
+	}{
+		{"lrc", lrcTime, 65432, "01:05.43"},
+		{"lrc rounds up", lrcTime, 59996, "01:00.00"},
+		{"lrc folds hours into minutes", lrcTime, 3723000, "62:03.00"},
+		{"srt", srtTime, 3723456, "01:02:03,456"},
+		{"cue", cueTime, 5007, "00:00:05.007"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.format(tt.ms))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// LRCExporter renders lyrics as LRC with ID tags and enhanced word tags
+type LRCExporter struct{}
+
+// ContentType implements Exporter
+func (e *LRCExporter) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// FileExtension implements Exporter
+func (e *LRCExporter) FileExtension() string {
+	return model.FormatLRC
+}
+
+// Export implements Exporter. Any [offset:] has already been applied to the
+// timestamps, so it is not written back.
+func (e *LRCExporter) Export(track model.Track, lyrics *model.LyricsData) ([]byte, error) {
+	cues, err := buildCues(lyrics)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	writeLRCHeader(&b, track, lyrics.Header)
+
+	for _, c := range cues {
+		b.WriteString("[" + lrcTime.format(c.startMs) + "]")
+		b.WriteString(singerPrefix(c.line.Singer))
+
+		if len(c.line.Words) == 0 {
+			b.WriteString(c.line.Text)
+		} else {
+			separators := wordSeparators(c.line.Text, c.line.Words)
+			for i, word := range c.line.Words {
+				b.WriteString(separators[i] + "<" + lrcTime.format(word.StartMs) + ">" + word.Text)
+			}
+			if last := c.line.Words[len(c.line.Words)-1]; last.EndMs > 0 {
+				b.WriteString("<" + lrcTime.format(last.EndMs) + ">")
+			}
+		}
+
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// wordSeparators returns the spacing to write before each timed word, taken
+// from the line text: syllables of one word such as "beau" + "tiful" stay
+// joined, and words missing from the text are separated by a space
+func wordSeparators(text string, words []model.Word) []string {
+	separators := make([]string, len(words))
+	cursor := 0
+
+	for i, word := range words {
+		at := strings.Index(text[cursor:], word.Text)
+		if at < 0 {
+			if i > 0 {
+				separators[i] = " "
+			}
+			continue
+		}
+
+		if i > 0 && at > 0 {
+			separators[i] = " "
+		}
+		cursor += at + len(word.Text)
+	}
+
+	return separators
+}
+
+// writeLRCHeader writes ID tags, preferring the parsed header over track metadata
+func writeLRCHeader(b *strings.Builder, track model.Track, header *model.LRCHeader) {
+	if header == nil {
+		header = &model.LRCHeader{}
+	}
+
+	tags := []struct {
+		name  string
+		value string
+		def   string
+	}{
+		{"ar", header.Artist, track.Artist},
+		{"ti", header.Title, track.Name},
+		{"al", header.Album, track.Album},
+		{"by", header.Author, ""},
+	}
+
+	for _, tag := range tags {
+		value := tag.value
+		if value == "" {
+			value = tag.def
+		}
+		if value != "" {
+			fmt.Fprintf(b, "[%s:%s]\n", tag.name, value)
+		}
+	}
+
+	if track.Duration > 0 {
+		fmt.Fprintf(b, "[length:%02d:%02d]\n", track.Duration/60, track.Duration%60)
+	}
+}
+
+// voiceCodeRegex matches the voice codes the parser reads in the bare
+// "v1: " form; every other singer needs the parenthesised form
+var voiceCodeRegex = regexp.MustCompile(`^(v\d+|[MFD])$`)
+
+// stripAttribution removes the characters that would end a "(Name:)" marker early
+var stripAttribution = strings.NewReplacer("(", "", ")", "", ":", "")
+
+// singerPrefix writes an LRC or SRT voice marker the parser reads back:
+// voice codes (v1, M, F, D) as "v1: " and performer names as "(Name:) ".
+// Parentheses and colons are dropped from names, since the marker cannot
+// hold them.
+func singerPrefix(singer string) string {
+	switch {
+	case singer == "":
+		return ""
+	case voiceCodeRegex.MatchString(singer):
+		return singer + ": "
+	}
+
+	if name := strings.TrimSpace(stripAttribution.Replace(singer)); name != "" {
+		return "(" + name + ":) "
+	}
+	return ""
+}
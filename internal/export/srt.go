@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// SRTExporter renders lyrics as SubRip subtitles
+type SRTExporter struct{}
+
+// ContentType implements Exporter
+func (e *SRTExporter) ContentType() string {
+	return "application/x-subrip; charset=utf-8"
+}
+
+// FileExtension implements Exporter
+func (e *SRTExporter) FileExtension() string {
+	return model.FormatSRT
+}
+
+// Export implements Exporter. Instrumental breaks produce no cue, and a
+// singer is written as the same speaker prefix LRC uses.
+func (e *SRTExporter) Export(track model.Track, lyrics *model.LyricsData) ([]byte, error) {
+	cues, err := buildCues(lyrics)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	index := 1
+
+	for _, c := range cues {
+		if c.line.IsBreak {
+			continue
+		}
+
+		if index > 1 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n", index, srtTime.format(c.startMs), srtTime.format(c.endMs), singerPrefix(c.line.Singer)+c.line.Text)
+		index++
+	}
+
+	return []byte(b.String()), nil
+}
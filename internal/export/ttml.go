@@ -0,0 +1,121 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// TTMLExporter renders lyrics as Apple-style TTML. Lines are grouped into
+// <div> sections split at instrumental breaks, and enhanced LRC words become
+// timed <span> elements with itunes:timing="Word".
+type TTMLExporter struct{}
+
+// ContentType implements Exporter
+func (e *TTMLExporter) ContentType() string {
+	return "application/ttml+xml; charset=utf-8"
+}
+
+// FileExtension implements Exporter
+func (e *TTMLExporter) FileExtension() string {
+	return model.FormatTTML
+}
+
+// Export implements Exporter
+func (e *TTMLExporter) Export(track model.Track, lyrics *model.LyricsData) ([]byte, error) {
+	cues, err := buildCues(lyrics)
+	if err != nil {
+		return nil, err
+	}
+
+	timing := "Line"
+	for _, c := range cues {
+		if len(c.line.Words) > 0 {
+			timing = "Word"
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<tt xmlns="http://www.w3.org/ns/ttml" xmlns:itunes="http://music.apple.com/lyric-ttml-internal" xmlns:ttm="http://www.w3.org/ns/ttml#metadata" itunes:timing="%s">`+"\n", timing)
+
+	b.WriteString("  <head>\n    <metadata>\n")
+	fmt.Fprintf(&b, "      <ttm:title>%s</ttm:title>\n", escapeXML(track.Name))
+	fmt.Fprintf(&b, "      <ttm:agent type=\"person\"><ttm:name>%s</ttm:name></ttm:agent>\n", escapeXML(track.Artist))
+	b.WriteString("    </metadata>\n  </head>\n")
+
+	if track.Duration > 0 {
+		fmt.Fprintf(&b, "  <body dur=\"%s\">\n", cueTime.format(track.Duration*1000))
+	} else {
+		b.WriteString("  <body>\n")
+	}
+
+	for _, section := range splitSections(cues) {
+		fmt.Fprintf(&b, "    <div begin=\"%s\" end=\"%s\">\n",
+			cueTime.format(section[0].startMs), cueTime.format(section[len(section)-1].endMs))
+
+		for _, c := range section {
+			fmt.Fprintf(&b, "      <p begin=\"%s\" end=\"%s\">", cueTime.format(c.startMs), cueTime.format(c.endMs))
+			writeTTMLText(&b, c)
+			b.WriteString("</p>\n")
+		}
+
+		b.WriteString("    </div>\n")
+	}
+
+	b.WriteString("  </body>\n</tt>\n")
+
+	return []byte(b.String()), nil
+}
+
+// writeTTMLText writes a line's text, as timed spans when word timings exist
+func writeTTMLText(b *strings.Builder, c cue) {
+	if len(c.line.Words) == 0 {
+		b.WriteString(escapeXML(c.line.Text))
+		return
+	}
+
+	for i, word := range c.line.Words {
+		end := word.EndMs
+		if end == 0 {
+			end = c.endMs
+		}
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(b, "<span begin=\"%s\" end=\"%s\">%s</span>", cueTime.format(word.StartMs), cueTime.format(end), escapeXML(word.Text))
+	}
+}
+
+// splitSections groups consecutive lyric cues, using breaks as separators
+func splitSections(cues []cue) [][]cue {
+	var sections [][]cue
+	var current []cue
+
+	for _, c := range cues {
+		if c.line.IsBreak {
+			if len(current) > 0 {
+				sections = append(sections, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, c)
+	}
+
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections
+}
+
+// escapeXML escapes text for use in XML content and attributes
+func escapeXML(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
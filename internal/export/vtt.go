@@ -0,0 +1,60 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// WebVTTExporter renders lyrics as WebVTT subtitles
+type WebVTTExporter struct{}
+
+// ContentType implements Exporter
+func (e *WebVTTExporter) ContentType() string {
+	return "text/vtt; charset=utf-8"
+}
+
+// FileExtension implements Exporter
+func (e *WebVTTExporter) FileExtension() string {
+	return model.FormatWebVTT
+}
+
+// Export implements Exporter. Instrumental breaks produce no cue, and a
+// singer becomes a <v Singer> voice span.
+func (e *WebVTTExporter) Export(track model.Track, lyrics *model.LyricsData) ([]byte, error) {
+	cues, err := buildCues(lyrics)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	for _, c := range cues {
+		if c.line.IsBreak {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n%s --> %s\n%s\n", cueTime.format(c.startMs), cueTime.format(c.endMs), vttVoice(c.line.Singer)+escapeVTT(c.line.Text))
+	}
+
+	return []byte(b.String()), nil
+}
+
+// stripVoiceName removes the characters that would end a voice tag early
+var stripVoiceName = strings.NewReplacer("<", "", ">", "", "\n", " ", "\r", "")
+
+// vttVoice opens a voice span for the singer, or returns an empty string
+// when there is no singer
+func vttVoice(singer string) string {
+	if name := strings.TrimSpace(stripVoiceName.Replace(singer)); name != "" {
+		return "<v " + name + ">"
+	}
+	return ""
+}
+
+// escapeVTT escapes characters that WebVTT treats as markup
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -3,20 +3,23 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/export"
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/service"
 )
 
 // SongHandler handles song analysis requests
 type SongHandler struct {
-	lyricsService *service.LyricsService
+	lyricsService service.SongAnalyzer
 }
 
 // NewSongHandler creates a new song handler
-func NewSongHandler(lyricsService *service.LyricsService) *SongHandler {
+func NewSongHandler(lyricsService service.SongAnalyzer) *SongHandler {
 	return &SongHandler{
 		lyricsService: lyricsService,
 	}
@@ -33,7 +36,30 @@ func (h *SongHandler) Analyze(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.lyricsService.AnalyzeSong(r.Context(), track, artist)
+	// Invalid values fall back to the default (false)
+	debug, _ := strconv.ParseBool(r.URL.Query().Get("debug"))
+	clean, _ := strconv.ParseBool(r.URL.Query().Get("clean"))
+
+	opts := service.AnalyzeOptions{
+		Debug:     debug,
+		Clean:     clean,
+		Include:   parseList(r.URL.Query()["include"]),
+		Exclude:   parseList(r.URL.Query()["exclude"]),
+		Languages: parseList(r.URL.Query()["language"]),
+	}
+
+	if value := r.URL.Query().Get("similarity"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			h.respondError(w, http.StatusBadRequest, "invalid_parameter", "Similarity must be a number greater than 0 and at most 1", map[string]string{
+				"similarity": value,
+			})
+			return
+		}
+		opts.SimilarityThreshold = threshold
+	}
+
+	response, err := h.lyricsService.AnalyzeSong(r.Context(), track, artist, opts)
 	if err != nil {
 		h.handleServiceError(w, track, artist, err)
 		return
@@ -42,6 +68,65 @@ func (h *SongHandler) Analyze(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// Lyrics renders a song's analyzed lyrics as a downloadable file.
+// The format query parameter selects lrc (default), srt, vtt or ttml.
+func (h *SongHandler) Lyrics(w http.ResponseWriter, r *http.Request) {
+	track := strings.TrimSpace(r.URL.Query().Get("track"))
+	artist := strings.TrimSpace(r.URL.Query().Get("artist"))
+
+	if track == "" || artist == "" {
+		h.respondError(w, http.StatusBadRequest, "missing_parameter", "Track and artist are required", nil)
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = model.FormatLRC
+	}
+
+	exporter, err := export.New(format)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid_parameter", "Format must be one of lrc, srt, vtt or ttml", map[string]string{
+			"format": format,
+		})
+		return
+	}
+
+	// A clean edit masks explicit words in the exported text
+	clean, _ := strconv.ParseBool(r.URL.Query().Get("clean"))
+
+	// Only the lines are rendered, so none of the analyzers need to run
+	opts := service.AnalyzeOptions{
+		Include: []string{service.FieldLyrics},
+		Clean:   clean,
+	}
+
+	response, err := h.lyricsService.AnalyzeSong(r.Context(), track, artist, opts)
+	if err != nil {
+		h.handleServiceError(w, track, artist, err)
+		return
+	}
+
+	body, err := exporter.Export(response.Track, response.Lyrics)
+	if err != nil {
+		if errors.Is(err, export.ErrNotSynced) {
+			h.respondError(w, http.StatusUnprocessableEntity, "not_synced", "No synced lyrics available to export", map[string]string{
+				"track":  track,
+				"artist": artist,
+			})
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "internal_error", "Failed to export lyrics", nil)
+		return
+	}
+
+	filename := response.Track.Name + "." + exporter.FileExtension()
+	w.Header().Set("Content-Type", exporter.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
 // handleServiceError maps service-layer errors to appropriate HTTP responses
 func (h *SongHandler) handleServiceError(w http.ResponseWriter, track, artist string, err error) {
 	statusCode := http.StatusInternalServerError
@@ -54,6 +139,26 @@ func (h *SongHandler) handleServiceError(w http.ResponseWriter, track, artist st
 	var timeoutErr *TimeoutError
 
 	switch {
+	case errors.Is(err, service.ErrInvalidThreshold):
+		statusCode = http.StatusBadRequest
+		code = "invalid_parameter"
+		message = "Similarity must be a number greater than 0 and at most 1"
+	case errors.Is(err, service.ErrUnknownSection):
+		statusCode = http.StatusBadRequest
+		code = "invalid_parameter"
+		message = "Include and exclude must name known sections"
+	case errors.Is(err, service.ErrUnknownLanguage):
+		statusCode = http.StatusBadRequest
+		code = "invalid_parameter"
+		message = "Language must be a supported ISO 639-1 code"
+	case errors.Is(err, service.ErrCleanUnavailable):
+		statusCode = http.StatusBadRequest
+		code = "invalid_parameter"
+		message = "Clean edits are not available"
+	case errors.Is(err, service.ErrInputTooLarge) || errors.Is(err, service.ErrTooManyLines) || errors.Is(err, service.ErrLineTooLong):
+		statusCode = http.StatusUnprocessableEntity
+		code = "lyrics_too_large"
+		message = "Lyrics exceed the parser's size limits"
 	case errors.As(err, &notFoundErr):
 		statusCode = http.StatusNotFound
 		code = "not_found"
@@ -92,6 +197,20 @@ func (h *SongHandler) handleServiceError(w http.ResponseWriter, track, artist st
 	})
 }
 
+// parseList splits comma-separated query values into lowercase names,
+// accepting both ?include=a,b and ?include=a&include=b
+func parseList(values []string) []string {
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
 // respondJSON sends a JSON response
 func (h *SongHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
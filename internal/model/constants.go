@@ -6,7 +6,40 @@ const (
 	LyricsTypePlain  = "plain"
 )
 
+// Timed lyrics format constants
+const (
+	FormatLRC    = "lrc"
+	FormatSRT    = "srt"
+	FormatWebVTT = "vtt"
+	FormatTTML   = "ttml" // export only
+)
+
+// Song section type constants
+const (
+	SectionIntro      = "intro"
+	SectionVerse      = "verse"
+	SectionPreChorus  = "pre-chorus"
+	SectionChorus     = "chorus"
+	SectionPostChorus = "post-chorus"
+	SectionBridge     = "bridge"
+	SectionInterlude  = "interlude"
+	SectionOutro      = "outro"
+	SectionOther      = "other"
+)
+
 // Source constants
 const (
 	SourceLRCLib = "lrclib"
 )
+
+// Parser diagnostic reasons
+const (
+	DiagnosticMissingTimestamp   = "missing timestamp"
+	DiagnosticMalformedTag       = "malformed tag"
+	DiagnosticUnsupportedTag     = "unsupported header tag"
+	DiagnosticInvalidTimestamp   = "invalid timestamp"
+	DiagnosticSecondsOutOfRange  = "seconds out of range"
+	DiagnosticOutOfOrder         = "timestamp out of order"
+	DiagnosticDuplicateTimestamp = "duplicate timestamp"
+	DiagnosticMalformedCue       = "malformed cue"
+)
@@ -4,29 +4,109 @@ package model
 type LyricLine struct {
 	LineNumber int     `json:"lineNumber"`
 	Timestamp  *string `json:"timestamp,omitempty"`
+	StartMs    *int    `json:"startMs,omitempty"`
+	EndMs      *int    `json:"endMs,omitempty"`
+	DurationMs *int    `json:"durationMs,omitempty"`
 	Text       string  `json:"text"`
+	Singer     string  `json:"singer,omitempty"` // voice or performer from duet markers
 	WordCount  int     `json:"wordCount"`
+	Words      []Word  `json:"words,omitempty"`
+	IsBreak    bool    `json:"isBreak,omitempty"`    // timed blank line marking an instrumental gap
+	RhymeGroup string  `json:"rhymeGroup,omitempty"` // end-rhyme letter within the line's section
+	Language   string  `json:"language,omitempty"`   // ISO 639-1 code, empty when the line is too short to tell
+	Explicit   bool    `json:"explicit,omitempty"`   // line contains an explicit word
+}
+
+// Word represents a single word with timing from enhanced LRC word tags
+type Word struct {
+	Text    string `json:"text"`
+	StartMs int    `json:"startMs"`
+	EndMs   int    `json:"endMs,omitempty"`
 }
 
 // LyricsData contains structured lyrics information
 type LyricsData struct {
-	Type          string      `json:"type"` // "synced" or "plain"
+	Type          string      `json:"type"`             // "synced" or "plain"
+	Format        string      `json:"format,omitempty"` // source format of synced lyrics: "lrc", "srt" or "vtt"
 	HasTimestamps bool        `json:"hasTimestamps"`
+	Tokenizer     string      `json:"tokenizer,omitempty"` // tokenizer used for the lines' word counts; statistics report their own
 	TotalLines    int         `json:"totalLines"`
-	Lines         []LyricLine `json:"lines"`
+	Header        *LRCHeader  `json:"header,omitempty"`
+	Language      *Language   `json:"language,omitempty"`
+	Lines         []LyricLine `json:"lines"` // null when excluded with exclude=lines
+}
+
+// Language is the main language of a song's lyrics
+type Language struct {
+	Code       string             `json:"code"`             // ISO 639-1, e.g. "es"
+	Confidence float64            `json:"confidence"`       // 0 to 1
+	Shares     map[string]float64 `json:"shares,omitempty"` // share of letters per language, only for songs that switch language
+}
+
+// LRCHeader contains the ID tags found at the top of an LRC file
+type LRCHeader struct {
+	Artist        string `json:"artist,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Album         string `json:"album,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Length        string `json:"length,omitempty"`
+	LengthSeconds int    `json:"lengthSeconds,omitempty"`
+	OffsetMs      int    `json:"offsetMs,omitempty"`
 }
 
 // Chorus represents detected chorus information
 type Chorus struct {
-	Detected    bool   `json:"detected"`
-	Text        string `json:"text,omitempty"`
-	Occurrences int    `json:"occurrences"`
-	LineNumbers []int  `json:"lineNumbers,omitempty"`
+	Detected    bool               `json:"detected"`
+	Text        string             `json:"text,omitempty"` // lines of a multi-line chorus joined by "\n"
+	Occurrences int                `json:"occurrences"`
+	LineNumbers []int              `json:"lineNumbers,omitempty"` // every line of every occurrence
+	Length      int                `json:"length,omitempty"`      // lyric lines per occurrence
+	Instances   []ChorusOccurrence `json:"instances,omitempty"`
+}
+
+// ChorusOccurrence is the line range of one repetition of the chorus
+type ChorusOccurrence struct {
+	StartLine int  `json:"startLine"`
+	EndLine   int  `json:"endLine"`
+	StartMs   *int `json:"startMs,omitempty"`
+	EndMs     *int `json:"endMs,omitempty"`
 }
 
 // Structure contains song structure analysis
 type Structure struct {
-	Chorus *Chorus `json:"chorus"`
+	Chorus   *Chorus   `json:"chorus"`
+	Sections []Section `json:"sections,omitempty"`
+	Form     string    `json:"form,omitempty"` // compact section sequence, e.g. "V-C-V-C-B-C"
+	Breaks   []Break   `json:"breaks,omitempty"`
+	Hook     *Hook     `json:"hook,omitempty"`
+}
+
+// Hook is the window most likely to hold the song's hook, for previews
+type Hook struct {
+	StartLine  int  `json:"startLine"`
+	EndLine    int  `json:"endLine"`
+	StartMs    *int `json:"startMs,omitempty"`
+	EndMs      *int `json:"endMs,omitempty"`
+	DurationMs *int `json:"durationMs,omitempty"`
+}
+
+// Section is a labelled part of a song covering a range of lines
+type Section struct {
+	Type      string `json:"type"`                // intro, verse, pre-chorus, chorus, bridge, outro, ...
+	Label     string `json:"label"`               // label as written, e.g. "Verse 2"
+	Performer string `json:"performer,omitempty"` // attribution from headers like [Verse 2: Artist]
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	StartMs   *int   `json:"startMs,omitempty"`
+	EndMs     *int   `json:"endMs,omitempty"`
+}
+
+// Break represents an instrumental gap marked by timed blank lines
+type Break struct {
+	LineNumber int  `json:"lineNumber"`
+	StartMs    int  `json:"startMs"`
+	EndMs      *int `json:"endMs,omitempty"`
+	DurationMs *int `json:"durationMs,omitempty"`
 }
 
 // Statistics contains lyrics statistics
@@ -37,4 +117,170 @@ type Statistics struct {
 	UniqueWords         int     `json:"uniqueWords"`
 	AverageWordsPerLine float64 `json:"averageWordsPerLine"`
 	RepetitionRatio     float64 `json:"repetitionRatio"`
+	Tokenizer           string  `json:"tokenizer"` // tokenizer every word figure is counted with
+
+	// Per-singer breakdown for duets and collaborations
+	Singers []SingerStats `json:"singers,omitempty"`
+
+	// Vocabulary-level statistics
+	LongestLine              *LongestLine `json:"longestLine,omitempty"`
+	MedianWordsPerLine       float64      `json:"medianWordsPerLine"`
+	AverageCharactersPerWord float64      `json:"averageCharactersPerWord"`
+}
+
+// SingerStats summarises one singer's part in a song
+type SingerStats struct {
+	Singer string  `json:"singer"`
+	Lines  int     `json:"lines"`
+	Words  int     `json:"words"`
+	Share  float64 `json:"share"` // fraction of all words in the song
+}
+
+// LongestLine identifies the line with the most words
+type LongestLine struct {
+	LineNumber int    `json:"lineNumber"`
+	Text       string `json:"text"`
+	WordCount  int    `json:"wordCount"`
+}
+
+// RhymeAnalysis describes the end-rhyme patterns of a song
+type RhymeAnalysis struct {
+	Schemes     []RhymeScheme `json:"schemes"`
+	Density     float64       `json:"density"`               // share of lyric lines that rhyme with another line in their section
+	Approximate bool          `json:"approximate,omitempty"` // the song or some of its lines are not in English, so those lines are rhymed by spelling
+}
+
+// RhymeScheme is the end-rhyme pattern of one section, e.g. "AABB"
+type RhymeScheme struct {
+	Label     string `json:"label,omitempty"` // section label, empty when split at breaks
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Scheme    string `json:"scheme"`
+}
+
+// Flow describes delivery speed from syllable and word counts. Rates are
+// only set for synced lyrics, where line durations are known.
+type Flow struct {
+	TotalSyllables          int           `json:"totalSyllables"`
+	AverageSyllablesPerLine float64       `json:"averageSyllablesPerLine"`
+	SyllablesPerSecond      float64       `json:"syllablesPerSecond,omitempty"` // over the time lines are sung
+	WordsPerSecond          float64       `json:"wordsPerSecond,omitempty"`
+	Lines                   []LineFlow    `json:"lines"`
+	Sections                []SectionFlow `json:"sections,omitempty"`
+	Timeline                []FlowPoint   `json:"timeline,omitempty"`
+	Approximate             bool          `json:"approximate,omitempty"` // the song or some of its lines are not in English, so those lines are counted by spelling
+}
+
+// LineFlow holds the syllable counts and delivery speed of one line
+type LineFlow struct {
+	LineNumber         int             `json:"lineNumber"`
+	Syllables          int             `json:"syllables"`
+	Words              []WordSyllables `json:"words"`
+	SyllablesPerSecond float64         `json:"syllablesPerSecond,omitempty"`
+	WordsPerSecond     float64         `json:"wordsPerSecond,omitempty"`
+}
+
+// WordSyllables is the syllable count of one word
+type WordSyllables struct {
+	Text      string `json:"text"`
+	Syllables int    `json:"syllables"`
+}
+
+// SectionFlow is the delivery speed of one section, for comparing verses
+type SectionFlow struct {
+	Label              string  `json:"label,omitempty"`
+	StartLine          int     `json:"startLine"`
+	EndLine            int     `json:"endLine"`
+	Syllables          int     `json:"syllables"`
+	SyllablesPerSecond float64 `json:"syllablesPerSecond,omitempty"`
+	WordsPerSecond     float64 `json:"wordsPerSecond,omitempty"`
+}
+
+// FlowPoint is the delivery density of a fixed time window
+type FlowPoint struct {
+	StartMs            int     `json:"startMs"`
+	EndMs              int     `json:"endMs"`
+	SyllablesPerSecond float64 `json:"syllablesPerSecond"`
+	WordsPerSecond     float64 `json:"wordsPerSecond"`
+}
+
+// Vocabulary describes lexical diversity and readability. Stemming applies
+// to English only. Readability uses the Flesch formulas with each lyric
+// line counted as a sentence.
+type Vocabulary struct {
+	TotalWords         int     `json:"totalWords"`
+	UniqueWords        int     `json:"uniqueWords"`
+	TypeTokenRatio     float64 `json:"typeTokenRatio"`           // unique words / total words
+	MTLD               float64 `json:"mtld"`                     // measure of textual lexical diversity, less sensitive to length than the ratio
+	HapaxLegomena      int     `json:"hapaxLegomena"`            // words used exactly once
+	StemmedUniqueWords int     `json:"stemmedUniqueWords"`       // unique words after stemming, so "love" and "loving" count once
+	LexicalDensity     float64 `json:"lexicalDensity,omitempty"` // share of words that are not stopwords, when the language has a stopword list
+	FleschKincaidGrade float64 `json:"fleschKincaidGrade"`
+	FleschReadingEase  float64 `json:"fleschReadingEase"`
+}
+
+// Sentiment polarities
+const (
+	PolarityPositive = "positive"
+	PolarityNegative = "negative"
+	PolarityNeutral  = "neutral"
+)
+
+// Sentiment describes the mood of a song from a bundled word lexicon.
+// Scores run from -1 (most negative) to 1 (most positive).
+type Sentiment struct {
+	Score       float64            `json:"score"` // mean of the line scores
+	Polarity    string             `json:"polarity"`
+	Emotions    map[string]int     `json:"emotions,omitempty"` // lines expressing each emotion
+	Lines       []LineSentiment    `json:"lines"`
+	Sections    []SectionSentiment `json:"sections,omitempty"`
+	Timeline    []SentimentPoint   `json:"timeline,omitempty"`
+	Approximate bool               `json:"approximate,omitempty"` // the song or some of its lines are not in English, so those lines are not scored
+}
+
+// LineSentiment is the sentiment of one line
+type LineSentiment struct {
+	LineNumber int      `json:"lineNumber"`
+	Score      float64  `json:"score"`
+	Polarity   string   `json:"polarity"`
+	Emotions   []string `json:"emotions,omitempty"`
+}
+
+// SectionSentiment is the sentiment of one section
+type SectionSentiment struct {
+	Label     string         `json:"label,omitempty"`
+	StartLine int            `json:"startLine"`
+	EndLine   int            `json:"endLine"`
+	Score     float64        `json:"score"`
+	Polarity  string         `json:"polarity"`
+	Emotions  map[string]int `json:"emotions,omitempty"`
+}
+
+// SentimentPoint is the sentiment of a fixed time window, for plotting the
+// emotional arc of synced lyrics
+type SentimentPoint struct {
+	StartMs int     `json:"startMs"`
+	EndMs   int     `json:"endMs"`
+	Score   float64 `json:"score"`
+}
+
+// Profanity describes the explicit words in a song
+type Profanity struct {
+	Explicit bool           `json:"explicit"`
+	Score    float64        `json:"score"` // share of lyric lines with an explicit word, 0 to 1
+	Count    int            `json:"count"` // explicit words found
+	Lines    []ExplicitLine `json:"lines,omitempty"`
+}
+
+// ExplicitLine lists the explicit words in one line
+type ExplicitLine struct {
+	LineNumber int            `json:"lineNumber"`
+	Words      []ExplicitWord `json:"words"`
+}
+
+// ExplicitWord is an explicit word and where it appears in its line
+type ExplicitWord struct {
+	Position int    `json:"position"` // 0-based index among the line's whitespace-separated words
+	Text     string `json:"text"`
+	Match    string `json:"match"` // word list entry it matched, e.g. "fuck*"
 }
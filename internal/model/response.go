@@ -4,21 +4,37 @@ import "time"
 
 // SongAnalysisResponse is the main API response
 type SongAnalysisResponse struct {
-	Track      Track       `json:"track"`
-	Lyrics     *LyricsData `json:"lyrics,omitempty"`
-	Structure  *Structure  `json:"structure,omitempty"`
-	Statistics *Statistics `json:"statistics,omitempty"`
-	Metadata   Metadata    `json:"metadata"`
+	Track      Track          `json:"track"`
+	Lyrics     *LyricsData    `json:"lyrics,omitempty"`
+	Structure  *Structure     `json:"structure,omitempty"`
+	Statistics *Statistics    `json:"statistics,omitempty"`
+	Rhyme      *RhymeAnalysis `json:"rhyme,omitempty"`
+	Flow       *Flow          `json:"flow,omitempty"`
+	Vocabulary *Vocabulary    `json:"vocabulary,omitempty"`
+	Sentiment  *Sentiment     `json:"sentiment,omitempty"`
+	Profanity  *Profanity     `json:"profanity,omitempty"`
+	Metadata   Metadata       `json:"metadata"`
+
+	// Extensions holds sections added by custom analyzers, keyed by analyzer name
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 // Metadata contains response metadata
 type Metadata struct {
-	Source           string    `json:"source"`
-	Cached           bool      `json:"cached"`
-	ProcessingTimeMs int64     `json:"processingTimeMs"`
-	Timestamp        time.Time `json:"timestamp"`
-	Warnings         []string  `json:"warnings,omitempty"`
-	Message          string    `json:"message,omitempty"`
+	Source           string       `json:"source"`
+	Cached           bool         `json:"cached"`
+	ProcessingTimeMs int64        `json:"processingTimeMs"`
+	Timestamp        time.Time    `json:"timestamp"`
+	Warnings         []string     `json:"warnings,omitempty"`
+	Diagnostics      []Diagnostic `json:"diagnostics,omitempty"`
+	Message          string       `json:"message,omitempty"`
+}
+
+// Diagnostic describes a source line the parser skipped or found suspicious
+type Diagnostic struct {
+	LineNumber int    `json:"lineNumber"` // 1-based line in the raw lyrics
+	Raw        string `json:"raw"`
+	Reason     string `json:"reason"`
 }
 
 // ErrorResponse represents an error response
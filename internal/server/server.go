@@ -11,7 +11,7 @@ import (
 )
 
 // NewRouter builds the application's HTTP router and registers routes
-func NewRouter(svc *service.LyricsService) http.Handler {
+func NewRouter(svc service.SongAnalyzer) http.Handler {
 	r := mux.NewRouter()
 
 	songHandler := handler.NewSongHandler(svc)
@@ -20,6 +20,7 @@ func NewRouter(svc *service.LyricsService) http.Handler {
 	api := r.PathPrefix("/api").Subrouter()
 
 	api.HandleFunc("/song/analyze", songHandler.Analyze).Methods(http.MethodGet)
+	api.HandleFunc("/song/lyrics", songHandler.Lyrics).Methods(http.MethodGet)
 
 	// Health check endpoint
 	r.HandleFunc("/health", healthHandler.Handle).Methods(http.MethodGet)
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// Built-in analyzer names
+const (
+	AnalyzerStructure  = "structure"
+	AnalyzerStatistics = "statistics"
+	AnalyzerRhyme      = "rhyme"
+	AnalyzerFlow       = "flow"
+	AnalyzerVocabulary = "vocabulary"
+	AnalyzerSentiment  = "sentiment"
+	AnalyzerProfanity  = "profanity"
+)
+
+// AnalysisInput is the parsed song handed to each analyzer
+type AnalysisInput struct {
+	Track model.Track
+
+	// Lines shares its backing array with the response, so analyzers that
+	// annotate lines update the returned lyrics too
+	Lines []model.LyricLine
+
+	// Synced reports whether the lines carry timestamps
+	Synced bool
+
+	// Sections are the labelled sections from plain lyrics section headers
+	Sections []model.Section
+
+	// Language is the song's main language as an ISO 639-1 code, empty
+	// when unknown. Analyzers use it to pick tokenizers and word lists.
+	Language string
+
+	// Options are the caller's analysis options
+	Options AnalyzeOptions
+}
+
+// StructureAnalyzer fills the structure section: chorus, sections, breaks and hook
+type StructureAnalyzer struct {
+	chorusDetector *ChorusDetector
+}
+
+// NewStructureAnalyzer creates a structure analyzer. A nil chorus detector
+// reports the chorus as not detected.
+func NewStructureAnalyzer(chorusDetector *ChorusDetector) *StructureAnalyzer {
+	return &StructureAnalyzer{
+		chorusDetector: chorusDetector,
+	}
+}
+
+// Name implements Analyzer
+func (a *StructureAnalyzer) Name() string {
+	return AnalyzerStructure
+}
+
+// Analyze implements Analyzer
+func (a *StructureAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	// A per-request threshold replaces the configured one
+	detector := a.chorusDetector
+	if threshold := input.Options.SimilarityThreshold; threshold > 0 && detector != nil {
+		detector = NewChorusDetectorWithThreshold(threshold)
+	}
+
+	// Detect chorus (graceful degradation - don't fail if chorus detection fails)
+	var chorus *model.Chorus
+	var keys []int
+	if detector != nil {
+		var err error
+		keys, err = detector.lineKeys(ctx, input.Lines)
+		if err != nil {
+			return err
+		}
+		chorus = detector.detectChorus(input.Lines, keys)
+	}
+
+	// If chorus detection failed or is nil, return empty chorus result
+	if chorus == nil {
+		chorus = &model.Chorus{Detected: false}
+	}
+
+	// Section headers are more reliable than inferred sections, so only
+	// songs without them are segmented
+	sections := input.Sections
+	if len(sections) == 0 && detector != nil {
+		sections = detector.segmentSong(input.Lines, chorus, keys)
+	}
+
+	structure := &model.Structure{
+		Chorus:   chorus,
+		Sections: sections,
+		Form:     songForm(sections),
+		Breaks:   collectBreaks(input.Lines),
+	}
+
+	timeStructure(structure, input.Lines)
+	structure.Hook = findHook(chorus, input.Lines, input.Track.Duration*1000)
+
+	response.Structure = structure
+
+	return nil
+}
+
+// StatisticsAnalyzer fills the statistics section
+type StatisticsAnalyzer struct {
+	statsCalc *StatisticsCalculator
+}
+
+// NewStatisticsAnalyzer creates a statistics analyzer. A nil calculator
+// leaves the statistics section out.
+func NewStatisticsAnalyzer(statsCalc *StatisticsCalculator) *StatisticsAnalyzer {
+	return &StatisticsAnalyzer{
+		statsCalc: statsCalc,
+	}
+}
+
+// Name implements Analyzer
+func (a *StatisticsAnalyzer) Name() string {
+	return AnalyzerStatistics
+}
+
+// Analyze implements Analyzer
+func (a *StatisticsAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	// Graceful degradation - omit statistics if no calculator is configured
+	if a.statsCalc == nil {
+		return nil
+	}
+
+	response.Statistics = a.statsCalc.CalculateForLanguage(input.Lines, input.Language)
+	return nil
+}
+
+// analysisSections returns the sections per-section analyzers report on:
+// those found by the structure analyzer, else the section headers, else
+// stanzas split at instrumental breaks
+func analysisSections(input *AnalysisInput, response *model.SongAnalysisResponse) []model.Section {
+	if response.Structure != nil && len(response.Structure.Sections) > 0 {
+		return response.Structure.Sections
+	}
+
+	if len(input.Sections) > 0 {
+		return input.Sections
+	}
+
+	if len(input.Lines) == 0 {
+		return nil
+	}
+
+	var sections []model.Section
+	for _, stanza := range splitStanzas(input.Lines, span{start: 0, end: len(input.Lines) - 1}) {
+		sections = append(sections, model.Section{
+			StartLine: input.Lines[stanza.start].LineNumber,
+			EndLine:   input.Lines[stanza.end].LineNumber,
+		})
+	}
+	return sections
+}
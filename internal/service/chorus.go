@@ -1,66 +1,427 @@
 package service
 
 import (
+	"cmp"
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
 )
 
+// DefaultSimilarityThreshold is how alike two normalized lines must be to
+// count as a repeat; 1 requires an exact match after normalization
+const DefaultSimilarityThreshold = 0.9
+
+// noLineKey marks lines without text, such as breaks, in line keys
+const noLineKey = -1
+
+// bagBins is the number of rune-count bins kept per line for the cheap
+// edit-distance bound; runes sharing a bin only weaken the bound
+const bagBins = 64
+
+// maxChorusLines is the longest repeated block considered as a chorus;
+// longer repeats are reported by their first maxChorusLines lines
+const maxChorusLines = 32
+
+// maxSimilarityWork bounds the edit-distance work, in compared cells, spent
+// matching near-duplicate lines in one song. Real songs stay far below it;
+// past it, long inputs fall back to matching exact repeats only.
+const maxSimilarityWork = 50_000_000
+
+// adLibRegex matches parenthesised ad-libs such as "(yeah)" or "(oh-oh)"
+var adLibRegex = regexp.MustCompile(`\([^()]*\)`)
+
 // ChorusDetector detects chorus sections in lyrics
-type ChorusDetector struct{}
+type ChorusDetector struct {
+	threshold float64
+}
 
-// NewChorusDetector creates a new chorus detector
+// NewChorusDetector creates a new chorus detector using the default similarity threshold
 func NewChorusDetector() *ChorusDetector {
-	return &ChorusDetector{}
+	return NewChorusDetectorWithThreshold(DefaultSimilarityThreshold)
+}
+
+// NewChorusDetectorWithThreshold creates a new chorus detector that treats
+// lines at least threshold similar (0 to 1) as repeats. Values outside
+// (0, 1] fall back to the default.
+func NewChorusDetectorWithThreshold(threshold float64) *ChorusDetector {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultSimilarityThreshold
+	}
+	return &ChorusDetector{
+		threshold: threshold,
+	}
+}
+
+// Threshold returns the similarity threshold used to match lines
+func (cd *ChorusDetector) Threshold() float64 {
+	return cd.threshold
+}
+
+// repeatedBlock is a run of lyric lines that repeats elsewhere in the song
+type repeatedBlock struct {
+	length int
+	starts []int // indexes of each non-overlapping occurrence
+	score  int   // words covered by all occurrences
 }
 
-// DetectChorus identifies repeated sections (chorus)
+// DetectChorus identifies the chorus as the repeated block of lines that
+// covers the most words, so a multi-line chorus is found as one unit and a
+// one-word ad-lib repeated a few times does not outweigh it.
 func (cd *ChorusDetector) DetectChorus(lines []model.LyricLine) *model.Chorus {
-	if len(lines) == 0 {
+	keys, _ := cd.lineKeys(context.Background(), lines)
+	return cd.detectChorus(lines, keys)
+}
+
+// detectChorus finds the chorus from keys computed by lineKeys
+func (cd *ChorusDetector) detectChorus(lines []model.LyricLine, keys []int) *model.Chorus {
+	// Breaks carry no text to repeat
+	var lyric []model.LyricLine
+	var lyricKeys []int
+	for i, line := range lines {
+		if keys[i] != noLineKey {
+			lyric = append(lyric, line)
+			lyricKeys = append(lyricKeys, keys[i])
+		}
+	}
+
+	weights := make([]int, len(lyric))
+	for i, line := range lyric {
+		weights[i] = max(1, line.WordCount)
+	}
+
+	block := findChorusBlock(lyricKeys, weights)
+	if block == nil {
 		return &model.Chorus{
 			Detected: false,
 		}
 	}
 
-	// Count occurrences of each line
-	lineCount := make(map[string][]int)
+	// The chorus text is taken from its first occurrence
+	first := block.starts[0]
+	texts := make([]string, 0, block.length)
+	for _, line := range lyric[first : first+block.length] {
+		texts = append(texts, line.Text)
+	}
+
+	var lineNumbers []int
+	var instances []model.ChorusOccurrence
+	for _, start := range block.starts {
+		for _, line := range lyric[start : start+block.length] {
+			lineNumbers = append(lineNumbers, line.LineNumber)
+		}
+		instances = append(instances, model.ChorusOccurrence{
+			StartLine: lyric[start].LineNumber,
+			EndLine:   lyric[start+block.length-1].LineNumber,
+		})
+	}
+
+	return &model.Chorus{
+		Detected:    true,
+		Text:        strings.Join(texts, "\n"),
+		Occurrences: len(block.starts),
+		LineNumbers: lineNumbers,
+		Length:      block.length,
+		Instances:   instances,
+	}
+}
+
+// lineShape is a normalized line prepared for near-duplicate matching
+type lineShape struct {
+	runes []rune
+	bag   [bagBins]int32 // rune counts, folded into bins
+}
+
+// newLineShape prepares a normalized line for matching
+func newLineShape(normalized string) lineShape {
+	shape := lineShape{runes: []rune(normalized)}
+	for _, r := range shape.runes {
+		shape.bag[r%bagBins]++
+	}
+	return shape
+}
+
+// lineKeys assigns each line the key of the first earlier line it matches,
+// so near-duplicates such as "Oh, baby baby" and "oh baby, baby!" compare
+// equal. Exact repeats are matched by their normalized text; only new text
+// is compared by edit distance, and once maxSimilarityWork is spent the
+// rest of the song is matched exactly. Lines without text get noLineKey.
+func (cd *ChorusDetector) lineKeys(ctx context.Context, lines []model.LyricLine) ([]int, error) {
+	keys := make([]int, len(lines))
+	exact := make(map[string]int)
+	var representatives []lineShape
+	budget := maxSimilarityWork
+
+	for i, line := range lines {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	for _, line := range lines {
 		if line.Text == "" {
+			keys[i] = noLineKey
+			continue
+		}
+
+		normalized := normalizeLine(line.Text)
+		if key, ok := exact[normalized]; ok {
+			keys[i] = key
 			continue
 		}
 
-		lineCount[line.Text] = append(lineCount[line.Text], line.LineNumber)
+		shape := newLineShape(normalized)
+		match := -1
+
+		// Matching against representatives in order keeps the result deterministic
+		if cd.threshold < 1 {
+			for k := range representatives {
+				if budget <= 0 {
+					break
+				}
+				if cd.similar(&shape, &representatives[k], &budget) {
+					match = k
+					break
+				}
+			}
+		}
+
+		if match < 0 {
+			match = len(representatives)
+			representatives = append(representatives, shape)
+		}
+		exact[normalized] = match
+		keys[i] = match
+	}
+
+	return keys, nil
+}
+
+// similar reports whether two lines meet the similarity threshold, charging
+// the work done to budget. Cheap bounds on the edit distance rule most
+// pairs out before the banded distance is computed.
+func (cd *ChorusDetector) similar(a, b *lineShape, budget *int) bool {
+	la, lb := len(a.runes), len(b.runes)
+	longest := max(la, lb)
+	if longest == 0 {
+		return true
+	}
+
+	limit := cd.maxDistance(longest)
+	if max(la-lb, lb-la) > limit {
+		return false
+	}
+
+	*budget -= bagBins
+	if bagDistance(a, b) > limit {
+		return false
+	}
+
+	*budget -= min(la, lb) * (2*limit + 1)
+	return boundedLevenshtein(a.runes, b.runes, limit) <= limit
+}
+
+// maxDistance returns the largest edit distance between lines of the given
+// length that still meets the threshold
+func (cd *ChorusDetector) maxDistance(longest int) int {
+	limit := int((1 - cd.threshold) * float64(longest))
+	for 1-float64(limit+1)/float64(longest) >= cd.threshold {
+		limit++
+	}
+	for limit > 0 && 1-float64(limit)/float64(longest) < cd.threshold {
+		limit--
+	}
+	return limit
+}
+
+// bagDistance is a lower bound on the edit distance: each edit changes the
+// rune counts of one line by at most one in each direction
+func bagDistance(a, b *lineShape) int {
+	more, fewer := 0, 0
+	for i := range a.bag {
+		if d := int(a.bag[i] - b.bag[i]); d > 0 {
+			more += d
+		} else {
+			fewer -= d
+		}
+	}
+	return max(more, fewer)
+}
+
+// normalizeLine lowercases text and drops ad-libs in parentheses, punctuation
+// and extra whitespace. A line that is only an ad-lib keeps its words.
+func normalizeLine(text string) string {
+	stripped := adLibRegex.ReplaceAllString(text, " ")
+	if strings.TrimFunc(stripped, isNotWordRune) == "" {
+		stripped = text
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return unicode.IsSpace(r) || (isNotWordRune(r) && r != '\'' && r != '’')
+	})
+	for i, field := range fields {
+		fields[i] = strings.Trim(field, "'’")
+	}
+
+	return strings.Join(strings.Fields(strings.Join(fields, " ")), " ")
+}
+
+// isNotWordRune reports whether r is neither a letter nor a number
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsMark(r)
+}
+
+// boundedLevenshtein returns the edit distance between two rune slices, or
+// limit+1 once it is known to exceed limit. Only cells within limit of the
+// diagonal can stay under the limit, so each row computes just that band.
+func boundedLevenshtein(a, b []rune, limit int) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	exceeded := limit + 1
+	if len(b)-len(a) > limit {
+		return exceeded
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = min(j, exceeded)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		lo, hi := max(1, i-limit), min(len(b), i+limit)
+		current[lo-1] = exceeded
+		if lo == 1 {
+			current[0] = min(i, exceeded)
+		}
+
+		rowMin := current[lo-1]
+		for j := lo; j <= hi; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost, exceeded)
+			rowMin = min(rowMin, current[j])
+		}
+		if hi < len(b) {
+			current[hi+1] = exceeded
+		}
+
+		if rowMin >= exceeded {
+			return exceeded
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// findChorusBlock returns the best repeated block of at most maxChorusLines
+// lines, or nil if no line repeats. Every repeated run shows up as a group
+// of adjacent suffixes in a suffix array of the keys, so the candidates are
+// read off its LCP intervals instead of comparing every pair of lines.
+// Blocks are ranked by words covered, then by occurrences, then by first
+// occurrence, so the result does not depend on sort order.
+func findChorusBlock(keys []int, weights []int) *repeatedBlock {
+	n := len(keys)
+	if n < 2 {
+		return nil
+	}
+
+	prefix := make([]int, n+1)
+	for i, weight := range weights {
+		prefix[i+1] = prefix[i] + weight
 	}
 
-	// Find the most repeated line (must appear at least 2 times)
-	var chorusLineNumbers []int
-	maxOccurrences := 0
+	// Suffixes only need ordering as far as the longest block considered
+	suffixes := make([]int, n)
+	for i := range suffixes {
+		suffixes[i] = i
+	}
+	slices.SortFunc(suffixes, func(a, b int) int {
+		for k := 0; k < maxChorusLines; k++ {
+			switch {
+			case a+k == n && b+k == n:
+				return 0
+			case a+k == n:
+				return -1
+			case b+k == n:
+				return 1
+			case keys[a+k] != keys[b+k]:
+				return cmp.Compare(keys[a+k], keys[b+k])
+			}
+		}
+		return 0
+	})
 
-	for _, lineNumbers := range lineCount {
-		if len(lineNumbers) >= 2 && len(lineNumbers) > maxOccurrences {
-			maxOccurrences = len(lineNumbers)
-			chorusLineNumbers = lineNumbers
+	// lcp[i] is the shared prefix of suffixes i-1 and i, capped at maxChorusLines
+	lcp := make([]int, n+1)
+	for i := 1; i < n; i++ {
+		a, b := suffixes[i-1], suffixes[i]
+		for lcp[i] < maxChorusLines && b+lcp[i] < n && a+lcp[i] < n && keys[a+lcp[i]] == keys[b+lcp[i]] {
+			lcp[i]++
 		}
 	}
 
-	if maxOccurrences == 0 {
-		return &model.Chorus{
-			Detected: false,
+	var best *repeatedBlock
+	var starts []int
+
+	// consider scores the blocks of lengths (parent, depth] that start at the
+	// suffixes in [left, right]; they share exactly those occurrences
+	consider := func(depth, parent, left, right int) {
+		occurrences := slices.Clone(suffixes[left : right+1])
+		slices.Sort(occurrences)
+
+		for length := parent + 1; length <= depth; length++ {
+			block := repeatedBlock{length: length, starts: starts[:0]}
+			for _, start := range occurrences {
+				if len(block.starts) > 0 && start < block.starts[len(block.starts)-1]+length {
+					continue
+				}
+				block.starts = append(block.starts, start)
+				block.score += prefix[start+length] - prefix[start]
+			}
+			starts = block.starts
+
+			if len(block.starts) >= 2 && block.betterThan(best) {
+				block.starts = slices.Clone(block.starts)
+				best = &block
+			}
 		}
 	}
 
-	// Find the text for the chorus (first occurrence)
-	var chorusText string
-	for text, lineNumbers := range lineCount {
-		if len(lineNumbers) == maxOccurrences {
-			chorusText = text
-			break
+	// Walk the LCP intervals bottom-up with a stack of open intervals
+	type interval struct{ depth, left int }
+	stack := []interval{{depth: 0, left: 0}}
+	for i := 1; i <= n; i++ {
+		left := i - 1
+		for lcp[i] < stack[len(stack)-1].depth {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			consider(top.depth, max(lcp[i], stack[len(stack)-1].depth), top.left, i-1)
+			left = top.left
+		}
+		if lcp[i] > stack[len(stack)-1].depth {
+			stack = append(stack, interval{depth: lcp[i], left: left})
 		}
 	}
 
-	return &model.Chorus{
-		Detected:    true,
-		Text:        chorusText,
-		Occurrences: maxOccurrences,
-		LineNumbers: chorusLineNumbers,
+	return best
+}
+
+// betterThan reports whether b should be preferred over other
+func (b *repeatedBlock) betterThan(other *repeatedBlock) bool {
+	switch {
+	case other == nil:
+		return true
+	case b.score != other.score:
+		return b.score > other.score
+	case len(b.starts) != len(other.starts):
+		return len(b.starts) > len(other.starts)
+	default:
+		return b.starts[0] < other.starts[0]
 	}
 }
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
@@ -91,3 +94,206 @@ func TestChorusDetector_DetectChorus_PreferMoreOccurrences(t *testing.T) {
 	assert.Equal(t, 4, chorus.Occurrences)
 }
 
+
+// songLines builds numbered lines with word counts; "" marks a break
+func songLines(texts ...string) []model.LyricLine {
+	lines := make([]model.LyricLine, len(texts))
+	for i, text := range texts {
+		lines[i] = model.LyricLine{
+			LineNumber: i + 1,
+			Text:       text,
+			WordCount:  len(strings.Fields(text)),
+			IsBreak:    text == "",
+		}
+	}
+	return lines
+}
+
+func TestChorusDetector_DetectChorus_MultiLineBlock(t *testing.T) {
+	detector := NewChorusDetector()
+
+	lines := songLines(
+		"Walking down the empty street",
+		"Hey",
+		"Sing it loud tonight",
+		"Let the whole world hear",
+		"Every word we say",
+		"Sing it loud tonight",
+		"Let the whole world hear",
+		"Hey",
+	)
+
+	chorus := detector.DetectChorus(lines)
+
+	assert.True(t, chorus.Detected)
+	assert.Equal(t, "Sing it loud tonight\nLet the whole world hear", chorus.Text)
+	assert.Equal(t, 2, chorus.Occurrences)
+	assert.Equal(t, 2, chorus.Length)
+	assert.Equal(t, []int{3, 4, 6, 7}, chorus.LineNumbers)
+	assert.Equal(t, []model.ChorusOccurrence{{StartLine: 3, EndLine: 4}, {StartLine: 6, EndLine: 7}}, chorus.Instances)
+}
+
+func TestChorusDetector_DetectChorus_TieIsDeterministic(t *testing.T) {
+	detector := NewChorusDetector()
+
+	lines := songLines("Line A", "Line B", "Verse", "Line B", "Other", "Line A")
+
+	for i := 0; i < 20; i++ {
+		chorus := detector.DetectChorus(lines)
+		assert.Equal(t, "Line A", chorus.Text)
+	}
+}
+
+func TestNormalizeLine(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Oh, baby baby", "oh baby baby"},
+		{"oh baby,   baby!", "oh baby baby"},
+		{"Don't stop (don't stop) believing", "don't stop believing"},
+		{"(Yeah, yeah)", "yeah yeah"},
+		{"¡Vamos! ¿Listos?", "vamos listos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeLine(tt.input))
+		})
+	}
+}
+
+func TestChorusDetector_DetectChorus_NearDuplicates(t *testing.T) {
+	lines := songLines(
+		"Oh, baby baby",
+		"Verse number one",
+		"oh baby, baby! (yeah)",
+		"Verse number two",
+		"Oh baby babe",
+	)
+
+	t.Run("default threshold matches normalized and near-duplicate lines", func(t *testing.T) {
+		chorus := NewChorusDetector().DetectChorus(lines)
+
+		assert.True(t, chorus.Detected)
+		assert.Equal(t, "Oh, baby baby", chorus.Text)
+		assert.Equal(t, []int{1, 3, 5}, chorus.LineNumbers)
+	})
+
+	t.Run("exact threshold only matches normalized lines", func(t *testing.T) {
+		chorus := NewChorusDetectorWithThreshold(1).DetectChorus(lines)
+
+		assert.True(t, chorus.Detected)
+		assert.Equal(t, []int{1, 3}, chorus.LineNumbers)
+	})
+
+	t.Run("out of range threshold uses the default", func(t *testing.T) {
+		assert.Equal(t, DefaultSimilarityThreshold, NewChorusDetectorWithThreshold(1.5).Threshold())
+	})
+}
+
+func TestBoundedLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"baby", "babe", 1, 1},
+		{"baby", "babe", 0, 1},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 2, 3}, // cut off past the limit
+		{"abc", "xyz", 3, 3},
+		{"abc", "xyz", 1, 2},
+		{"", "abc", 5, 3},
+		{"abc", "", 1, 2},
+		{"same line", "same line", 0, 0},
+		{"abcdef", "badcfe", 1, 2},
+		{"short", "much longer line", 2, 3}, // length difference alone exceeds the limit
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%d", tt.a, tt.b, tt.limit), func(t *testing.T) {
+			assert.Equal(t, tt.want, boundedLevenshtein([]rune(tt.a), []rune(tt.b), tt.limit))
+		})
+	}
+}
+
+func TestChorusDetector_MaxDistance(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		longest   int
+		want      int
+	}{
+		{0.9, 10, 1},
+		{0.9, 9, 0},
+		{0.75, 4, 1},
+		{1, 20, 0},
+		{0.5, 7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v/%d", tt.threshold, tt.longest), func(t *testing.T) {
+			assert.Equal(t, tt.want, NewChorusDetectorWithThreshold(tt.threshold).maxDistance(tt.longest))
+		})
+	}
+}
+
+func TestChorusDetector_LineKeys(t *testing.T) {
+	detector := NewChorusDetector()
+	lines := songLines("Oh, baby baby", "Verse line", "", "oh baby, baby!", "Oh baby babe", "Verse line")
+
+	keys, err := detector.lineKeys(context.Background(), lines)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, noLineKey, 0, 0, 1}, keys)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = detector.lineKeys(ctx, lines)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestChorusDetector_DetectChorus_LongRepeatIsCapped(t *testing.T) {
+	texts := make([]string, 0, 2*(maxChorusLines+8))
+	for range 2 {
+		for i := range maxChorusLines + 8 {
+			texts = append(texts, fmt.Sprintf("Line %d of a very long repeated passage", i))
+		}
+	}
+
+	chorus := NewChorusDetectorWithThreshold(1).DetectChorus(songLines(texts...))
+
+	assert.True(t, chorus.Detected)
+	assert.Equal(t, maxChorusLines, chorus.Length)
+	assert.Equal(t, 2, chorus.Occurrences)
+}
+
+// benchmarkLines builds DefaultMaxLines lines from a repeating pattern of
+// distinct line texts
+func benchmarkLines(distinct int) []model.LyricLine {
+	texts := make([]string, DefaultMaxLines)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("line %d of the song", i%distinct)
+	}
+	return songLines(texts...)
+}
+
+func BenchmarkChorusDetector_DetectChorus(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		lines []model.LyricLine
+	}{
+		{"distinct", benchmarkLines(DefaultMaxLines)},
+		{"verse and chorus", benchmarkLines(12)},
+		{"one line", benchmarkLines(1)},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			detector := NewChorusDetector()
+			for b.Loop() {
+				detector.DetectChorus(bm.lines)
+			}
+		})
+	}
+}
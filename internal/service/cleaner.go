@@ -0,0 +1,206 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// Kinds of non-lyric lines the cleaner removes
+const (
+	JunkMusic        = "music"        // only music notes, e.g. "♪"
+	JunkInstrumental = "instrumental" // "[Instrumental]" placeholder
+	JunkCredit       = "credit"       // "Lyrics by …", "Written by:", translator credits, followed only by names
+	JunkURL          = "url"          // site addresses with a scheme or www., or ending the line
+	JunkAd           = "ad"           // "Find more lyrics at …"
+	JunkCustom       = "custom"       // matched a user rule
+)
+
+// cleanRule matches one kind of non-lyric line
+type cleanRule struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+// creditName matches a name in a credit line, such as "J. Smith" or
+// "Ludwig van Beethoven", so a lyric like "Words by the fire" is kept
+const creditName = `[\p{Lu}\p{Lo}][\p{L}\p{M}'’.-]*(\s+([\p{Lu}\p{Lo}][\p{L}\p{M}'’.-]*|de|da|del|di|du|la|le|van|von|der|y))*`
+
+// builtinCleanRules recognise the junk lines lyrics sites commonly add
+var builtinCleanRules = []cleanRule{
+	{JunkMusic, regexp.MustCompile(`^[\s♩♪♫♬🎵🎶~*.…-]*[♩♪♫♬🎵🎶][\s♩♪♫♬🎵🎶~*.…-]*$`)},
+	{JunkInstrumental, regexp.MustCompile(`(?i)^[\s♪*(\[{-]*instrumental(\s+(break|solo|interlude))?[\s♪*)\]}.-]*$`)},
+	{JunkCredit, regexp.MustCompile(`^(?i:[\s(\[*]*(lyrics|words|music|written|composed|produced|arranged|translated|translation|transcribed|subtitles|songwriters?|composers?|lyricists?|producers?|translators?|writers?)(\s+(and|&)\s+(music|lyrics))?\s*(by\b\s*:?|:))\s*` +
+		creditName + `(\s*(,|&|/|;|\+|\band\b|\bfeat\.|\bwith\b)\s*` + creditName + `)*[\s)\]*.]*$`)},
+	{JunkURL, regexp.MustCompile(`(?i)(https?://|\bwww\.|\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|io|fm|co|ru|de|fr|es|br)(/\S*)?$)`)},
+	{JunkAd, regexp.MustCompile(`(?i)(lyrics\s+(powered|provided|licensed)\s+by|(find|get|see|read)\s+more\s+lyrics|more\s+lyrics\s+(at|on)|lyrics\s+(found\s+)?(at|on)\s+\S+\.\S+)`)},
+}
+
+// removedLine is a line the cleaner took out and the rule it matched
+type removedLine struct {
+	line    model.LyricLine
+	kind    string
+	asBreak bool // kept as an instrumental break rather than dropped
+}
+
+// cleanResult holds the lines left after cleaning
+type cleanResult struct {
+	lines        []model.LyricLine
+	sections     []model.Section
+	removed      []removedLine
+	instrumental bool
+}
+
+// LineCleaner removes lines that are not lyrics, such as credits, site
+// addresses, ads and music-note placeholders, so they do not skew the
+// statistics or the chorus detection
+type LineCleaner struct {
+	rules []cleanRule
+}
+
+// NewLineCleaner creates a line cleaner with the built-in rules
+func NewLineCleaner() *LineCleaner {
+	return &LineCleaner{rules: builtinCleanRules}
+}
+
+// NewLineCleanerWithRules creates a line cleaner with the built-in rules
+// followed by user rules, each a regular expression matched against a
+// line's text
+func NewLineCleanerWithRules(patterns []string) (*LineCleaner, error) {
+	rules := append([]cleanRule(nil), builtinCleanRules...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clean rule %q: %w", pattern, err)
+		}
+		rules = append(rules, cleanRule{kind: JunkCustom, pattern: re})
+	}
+	return &LineCleaner{rules: rules}, nil
+}
+
+// LoadCleanRules reads user rules from path, one regular expression per
+// line, skipping blank lines and # comments. An empty path loads nothing.
+func LoadCleanRules(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clean rules: %w", err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// match returns the kind of junk text is, or "" for a lyric line
+func (c *LineCleaner) match(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	for _, rule := range c.rules {
+		if rule.pattern.MatchString(text) {
+			return rule.kind
+		}
+	}
+	return ""
+}
+
+// clean removes junk lines and renumbers the rest, moving section bounds
+// with them. A timed placeholder such as "♪" marks an instrumental gap, so
+// it becomes a break instead of being dropped. The song counts as
+// instrumental when no lyric line is left and it had instrumental
+// placeholders; lyrics emptied by removing credits or other junk do not.
+func (c *LineCleaner) clean(lines []model.LyricLine, sections []model.Section) cleanResult {
+	result := cleanResult{}
+	renumbered := make(map[int]int, len(lines))
+	placeholders := false
+	lyricLines := 0
+
+	for _, line := range lines {
+		if !line.IsBreak {
+			if kind := c.match(line.Text); kind != "" {
+				placeholder := kind == JunkMusic || kind == JunkInstrumental
+				asBreak := placeholder && line.StartMs != nil
+				result.removed = append(result.removed, removedLine{line: line, kind: kind, asBreak: asBreak})
+				placeholders = placeholders || placeholder
+
+				if !asBreak {
+					continue
+				}
+				line.Text, line.WordCount, line.Words, line.IsBreak = "", 0, nil, true
+			}
+		}
+
+		if !line.IsBreak {
+			lyricLines++
+		}
+		renumbered[line.LineNumber] = len(result.lines) + 1
+		line.LineNumber = len(result.lines) + 1
+		result.lines = append(result.lines, line)
+	}
+
+	result.instrumental = lyricLines == 0 && placeholders
+
+	if len(result.removed) == 0 {
+		result.sections = sections
+		return result
+	}
+
+	for _, section := range sections {
+		start, end := 0, 0
+		for old := section.StartLine; old <= section.EndLine; old++ {
+			if n, ok := renumbered[old]; ok {
+				if start == 0 {
+					start = n
+				}
+				end = n
+			}
+		}
+		if start == 0 {
+			continue
+		}
+		section.StartLine, section.EndLine = start, end
+		result.sections = append(result.sections, section)
+	}
+
+	return result
+}
+
+// marksInstrumental reports whether any line of the raw lyrics is an
+// instrumental placeholder, such as a lone [Instrumental] header the plain
+// lyrics parser read as an empty section
+func (c *LineCleaner) marksInstrumental(raw string) bool {
+	for _, line := range strings.Split(raw, "\n") {
+		if c.match(line) == JunkInstrumental {
+			return true
+		}
+	}
+	return false
+}
+
+// warnings describes each removed line by its lyric line number before
+// cleaning, labelled apart from the source line numbers diagnostics cite
+func (r cleanResult) warnings() []string {
+	var warnings []string
+	for _, removed := range r.removed {
+		action := "removed"
+		if removed.asBreak {
+			action = "replaced with a break"
+		}
+		warnings = append(warnings, fmt.Sprintf("lyric line %d: %s line %s: %q", removed.line.LineNumber, removed.kind, action, removed.line.Text))
+	}
+	return warnings
+}
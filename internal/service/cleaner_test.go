@@ -0,0 +1,167 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineCleaner_Match(t *testing.T) {
+	tests := []struct {
+		text string
+		kind string
+	}{
+		{"♪", JunkMusic},
+		{"♪ ♫ ♪", JunkMusic},
+		{"~ 🎶 ~", JunkMusic},
+		{"[Instrumental]", JunkInstrumental},
+		{"(instrumental break)", JunkInstrumental},
+		{"Lyrics by John Smith", JunkCredit},
+		{"Written by: Jane Doe", JunkCredit},
+		{"Words and Music by J. Smith", JunkCredit},
+		{"Translated by Maria", JunkCredit},
+		{"Songwriters: A, B", JunkCredit},
+		{"(Music by Ludwig van Beethoven & Jay-Z)", JunkCredit},
+		{"Lyrics by: José Pérez, Ana María", JunkCredit},
+		{"https://example.com/song", JunkURL},
+		{"Visit www.lyricsite.net", JunkURL},
+		{"lyricsfreak.com", JunkURL},
+		{"Find more lyrics at our site", JunkAd},
+		{"Lyrics powered by a provider", JunkAd},
+		{"I love you so much", ""},
+		{"♪ I love you so much ♪", ""},
+		{"Music makes the people come together", ""},
+		{"Written in the stars", ""},
+		{"Instrumental to my heart", ""},
+		{"Oh yeah. Come on", ""},
+		{"Words by the fire, we sing", ""},
+		{"Music: it's all I need", ""},
+		{"Lyrics by heart, I know every line", ""},
+		{"Written by John, but sung by me", ""},
+		{"I'm at the bar.co and you know", ""},
+		{"Eres mi vida.es lo que siento", ""},
+		{"Lyrics at songs.example.fm", JunkURL},
+		{"", ""},
+	}
+
+	cleaner := NewLineCleaner()
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.kind, cleaner.match(tt.text))
+		})
+	}
+}
+
+func TestLineCleaner_Clean_Plain(t *testing.T) {
+	lines := songLines(
+		"Lyrics by John Smith",
+		"First verse line",
+		"Second verse line",
+		"Chorus line",
+		"www.lyricsite.com",
+		"Chorus line again",
+	)
+	sections := []model.Section{
+		{Type: model.SectionVerse, Label: "Verse", StartLine: 1, EndLine: 3},
+		{Type: model.SectionChorus, Label: "Chorus", StartLine: 4, EndLine: 6},
+	}
+
+	result := NewLineCleaner().clean(lines, sections)
+
+	var texts []string
+	for i, line := range result.lines {
+		texts = append(texts, line.Text)
+		assert.Equal(t, i+1, line.LineNumber)
+	}
+	assert.Equal(t, []string{"First verse line", "Second verse line", "Chorus line", "Chorus line again"}, texts)
+	assert.Equal(t, []model.Section{
+		{Type: model.SectionVerse, Label: "Verse", StartLine: 1, EndLine: 2},
+		{Type: model.SectionChorus, Label: "Chorus", StartLine: 3, EndLine: 4},
+	}, result.sections)
+	assert.False(t, result.instrumental)
+	assert.Equal(t, []string{
+		`lyric line 1: credit line removed: "Lyrics by John Smith"`,
+		`lyric line 5: url line removed: "www.lyricsite.com"`,
+	}, result.warnings())
+}
+
+func TestLineCleaner_Clean_Synced(t *testing.T) {
+	lines, err := NewParser().ParseSyncedLyrics(`[00:01.00] Hello darkness
+[00:05.00] ♪
+[00:20.00] My old friend
+[00:25.00] Lyrics by Someone`)
+	require.NoError(t, err)
+
+	result := NewLineCleaner().clean(lines, nil)
+
+	require.Len(t, result.lines, 3)
+	assert.True(t, result.lines[1].IsBreak)
+	assert.Empty(t, result.lines[1].Text)
+	assert.Equal(t, 5000, *result.lines[1].StartMs)
+	assert.Equal(t, 2, result.lines[1].LineNumber)
+	assert.Equal(t, "My old friend", result.lines[2].Text)
+	assert.False(t, result.instrumental)
+	assert.Equal(t, []string{
+		`lyric line 2: music line replaced with a break: "♪"`,
+		`lyric line 4: credit line removed: "Lyrics by Someone"`,
+	}, result.warnings())
+}
+
+func TestLineCleaner_Clean_Instrumental(t *testing.T) {
+	tests := []struct {
+		name         string
+		lines        []model.LyricLine
+		instrumental bool
+	}{
+		{"music notes only", songLines("♪", "", "♪ ♪"), true},
+		{"instrumental placeholder", songLines("[Instrumental]"), true},
+		{"no lines left by the parser", nil, false},
+		{"credits only", songLines("Lyrics by John Smith"), false},
+		{"lyrics with a placeholder", songLines("♪", "Hello darkness"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.instrumental, NewLineCleaner().clean(tt.lines, nil).instrumental)
+		})
+	}
+}
+
+func TestLineCleaner_MarksInstrumental(t *testing.T) {
+	cleaner := NewLineCleaner()
+
+	assert.True(t, cleaner.marksInstrumental("[Instrumental]\r\n"))
+	assert.False(t, cleaner.marksInstrumental("[ar:Artist]\n[ti:Song]"))
+	assert.False(t, cleaner.marksInstrumental(""))
+}
+
+func TestNewLineCleanerWithRules(t *testing.T) {
+	cleaner, err := NewLineCleanerWithRules([]string{`(?i)^sync(ed)? by`})
+	require.NoError(t, err)
+
+	assert.Equal(t, JunkCustom, cleaner.match("Synced by Someone"))
+	assert.Equal(t, JunkCredit, cleaner.match("Lyrics by Someone"), "built-in rules are kept")
+
+	_, err = NewLineCleanerWithRules([]string{"("})
+	assert.Error(t, err)
+}
+
+func TestLoadCleanRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# site footers\n^Synced by\n\n(?i)karaoke version\n"), 0o644))
+
+	rules, err := LoadCleanRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"^Synced by", "(?i)karaoke version"}, rules)
+
+	rules, err = LoadCleanRules("")
+	assert.NoError(t, err)
+	assert.Nil(t, rules)
+
+	_, err = LoadCleanRules(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
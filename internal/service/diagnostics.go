@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// diagnosticsCollector records lines the parser skipped or found suspicious
+type diagnosticsCollector struct {
+	entries []model.Diagnostic
+}
+
+// add records a diagnostic for a raw source line
+func (dc *diagnosticsCollector) add(lineNumber int, raw, reason string) {
+	dc.entries = append(dc.entries, model.Diagnostic{
+		LineNumber: lineNumber,
+		Raw:        raw,
+		Reason:     reason,
+	})
+}
+
+// list returns the collected diagnostics ordered by source line
+func (dc *diagnosticsCollector) list() []model.Diagnostic {
+	sort.SliceStable(dc.entries, func(i, j int) bool {
+		return dc.entries[i].LineNumber < dc.entries[j].LineNumber
+	})
+	return dc.entries
+}
+
+// diagnosticWarnings formats diagnostics as human-readable warnings, citing
+// lines by their number in the source text
+func diagnosticWarnings(diagnostics []model.Diagnostic) []string {
+	var warnings []string
+	for _, d := range diagnostics {
+		warnings = append(warnings, fmt.Sprintf("source line %d: %s: %q", d.LineNumber, d.Reason, d.Raw))
+	}
+	return warnings
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseSyncedLyricsWithDiagnostics(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedLines int
+		expected      []model.Diagnostic
+	}{
+		{
+			name:          "clean lyrics with header",
+			input:         "[ar:Artist]\n[offset:0]\n[00:10.00] One\n[00:15.00] Two",
+			expectedLines: 2,
+			expected:      nil,
+		},
+		{
+			name:          "missing timestamp and malformed tag",
+			input:         "[00:10.00] One\nPlain text\n[0:1.5] Short tag\n[invalid] Bad",
+			expectedLines: 1,
+			expected: []model.Diagnostic{
+				{LineNumber: 2, Raw: "Plain text", Reason: model.DiagnosticMissingTimestamp},
+				{LineNumber: 3, Raw: "[0:1.5] Short tag", Reason: model.DiagnosticMalformedTag},
+				{LineNumber: 4, Raw: "[invalid] Bad", Reason: model.DiagnosticMalformedTag},
+			},
+		},
+		{
+			name:          "unsupported header tag",
+			input:         "[re:LRC Editor]\n[00:10.00] One",
+			expectedLines: 1,
+			expected: []model.Diagnostic{
+				{LineNumber: 1, Raw: "[re:LRC Editor]", Reason: model.DiagnosticUnsupportedTag},
+			},
+		},
+		{
+			name:          "seconds out of range kept with warning",
+			input:         "[01:75.00] Odd",
+			expectedLines: 1,
+			expected: []model.Diagnostic{
+				{LineNumber: 1, Raw: "[01:75.00] Odd", Reason: model.DiagnosticSecondsOutOfRange},
+			},
+		},
+		{
+			name:          "out of order timestamps",
+			input:         "[00:20.00] Second\n[00:10.00] First",
+			expectedLines: 2,
+			expected: []model.Diagnostic{
+				{LineNumber: 2, Raw: "[00:10.00] First", Reason: model.DiagnosticOutOfOrder},
+			},
+		},
+		{
+			name:          "duplicate timestamps",
+			input:         "[00:10.00] One\n[00:10.00] Also one",
+			expectedLines: 2,
+			expected: []model.Diagnostic{
+				{LineNumber: 2, Raw: "[00:10.00] Also one", Reason: model.DiagnosticDuplicateTimestamp},
+			},
+		},
+		{
+			name:          "compressed lyrics are not reported out of order",
+			input:         "[00:10.00][00:30.00] Chorus\n[00:20.00] Verse",
+			expectedLines: 3,
+			expected:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			lines, diagnostics, err := parser.ParseSyncedLyricsWithDiagnostics(tt.input)
+
+			assert.NoError(t, err)
+			assert.Len(t, lines, tt.expectedLines)
+			assert.Equal(t, tt.expected, diagnostics)
+		})
+	}
+}
+
+func TestDiagnosticWarnings(t *testing.T) {
+	warnings := diagnosticWarnings([]model.Diagnostic{
+		{LineNumber: 3, Raw: "[bad] tag", Reason: model.DiagnosticMalformedTag},
+	})
+
+	assert.Equal(t, []string{`source line 3: malformed tag: "[bad] tag"`}, warnings)
+}
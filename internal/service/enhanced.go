@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// parseWordTimestamps extracts enhanced LRC (A2) word tags from line text.
+// It returns the clean text with all tags removed and the timed words.
+// Words is nil when the text contains no word tags.
+func (p *Parser) parseWordTimestamps(text string, lineStartMs int) (string, []model.Word) {
+	tags := p.wordTimestampRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(tags) == 0 {
+		return text, nil
+	}
+
+	var words []model.Word
+
+	// Text before the first tag starts with the line itself
+	if prefix := strings.TrimSpace(text[:tags[0][0]]); prefix != "" {
+		words = append(words, model.Word{Text: prefix, StartMs: lineStartMs})
+	}
+
+	for i, tag := range tags {
+		timestamp := fmt.Sprintf("%s:%s", text[tag[2]:tag[3]], text[tag[4]:tag[5]])
+		seconds, err := p.ParseTimestamp(timestamp)
+		if err != nil {
+			continue
+		}
+		startMs := toMilliseconds(seconds)
+
+		// A tag closes the previous word
+		if len(words) > 0 && words[len(words)-1].EndMs == 0 {
+			words[len(words)-1].EndMs = startMs
+		}
+
+		end := len(text)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+
+		// A tag followed by no text only marks the end of the previous word
+		segment := strings.TrimSpace(text[tag[1]:end])
+		if segment == "" {
+			continue
+		}
+
+		words = append(words, model.Word{Text: segment, StartMs: startMs})
+	}
+
+	cleanText := strings.Join(strings.Fields(p.wordTimestampRegex.ReplaceAllString(text, "")), " ")
+
+	return cleanText, words
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseSyncedLyrics_EnhancedWordTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedLines int
+		validate      func(t *testing.T, lines []model.LyricLine)
+	}{
+		{
+			name:          "word tags with trailing end tag",
+			input:         "[00:12.00]<00:12.00>Never <00:12.50>gonna <00:13.00>give <00:13.40>you <00:13.80>up<00:14.50>",
+			expectedLines: 1,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, "Never gonna give you up", lines[0].Text)
+				assert.Equal(t, 5, lines[0].WordCount)
+				assert.Equal(t, []model.Word{
+					{Text: "Never", StartMs: 12000, EndMs: 12500},
+					{Text: "gonna", StartMs: 12500, EndMs: 13000},
+					{Text: "give", StartMs: 13000, EndMs: 13400},
+					{Text: "you", StartMs: 13400, EndMs: 13800},
+					{Text: "up", StartMs: 13800, EndMs: 14500},
+				}, lines[0].Words)
+			},
+		},
+		{
+			name:          "last word without end tag",
+			input:         "[00:01.00]<00:01.00>Hello <00:01.500>world",
+			expectedLines: 1,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, "Hello world", lines[0].Text)
+				assert.Len(t, lines[0].Words, 2)
+				assert.Equal(t, 1500, lines[0].Words[1].StartMs)
+				assert.Equal(t, 0, lines[0].Words[1].EndMs)
+			},
+		},
+		{
+			name:          "untagged prefix starts at line timestamp",
+			input:         "[00:05.00]Oh <00:05.80>yeah",
+			expectedLines: 1,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, "Oh yeah", lines[0].Text)
+				assert.Equal(t, model.Word{Text: "Oh", StartMs: 5000, EndMs: 5800}, lines[0].Words[0])
+				assert.Equal(t, 2, lines[0].WordCount)
+			},
+		},
+		{
+			name:          "syllable tags join into clean text",
+			input:         "[00:02.00]<00:02.00>beau<00:02.20>ti<00:02.40>ful<00:03.00>",
+			expectedLines: 1,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, "beautiful", lines[0].Text)
+				assert.Equal(t, 1, lines[0].WordCount)
+				assert.Len(t, lines[0].Words, 3)
+			},
+		},
+		{
+			name:          "plain LRC line has no words",
+			input:         "[00:10.00] Just a line",
+			expectedLines: 1,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, "Just a line", lines[0].Text)
+				assert.Nil(t, lines[0].Words)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			lines, err := parser.ParseSyncedLyrics(tt.input)
+
+			assert.NoError(t, err)
+			assert.Len(t, lines, tt.expectedLines)
+
+			if tt.validate != nil {
+				tt.validate(t, lines)
+			}
+		})
+	}
+}
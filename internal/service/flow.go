@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// flowWindowMs is the width of each point on the flow-density timeline
+const flowWindowMs = 10000
+
+// timedCount is the syllable and word count of a line sung over a known time
+type timedCount struct {
+	lineNumber int
+	startMs    int
+	endMs      int
+	syllables  int
+	words      int
+}
+
+// FlowAnalyzer measures delivery speed: syllables per line and word, and for
+// synced lyrics syllables and words per second overall, per section and
+// over time. Lines not in English are counted by spelling rules only and
+// the result is marked approximate.
+type FlowAnalyzer struct {
+	tokenizer Tokenizer
+	syllables *SyllableCounter
+}
+
+// NewFlowAnalyzer creates a flow analyzer using the bundled pronunciation dictionary
+func NewFlowAnalyzer() *FlowAnalyzer {
+	return &FlowAnalyzer{
+		tokenizer: NewUnicodeTokenizer(),
+		syllables: NewSyllableCounter(),
+	}
+}
+
+// Name implements Analyzer
+func (a *FlowAnalyzer) Name() string {
+	return AnalyzerFlow
+}
+
+// Analyze implements Analyzer
+func (a *FlowAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	flow := &model.Flow{Lines: []model.LineFlow{}, Approximate: englishDataApproximate(input)}
+	var timed []timedCount
+
+	for _, line := range input.Lines {
+		if line.IsBreak || line.Text == "" {
+			continue
+		}
+
+		words := a.tokenizer.Tokenize(line.Text)
+		lineFlow := model.LineFlow{
+			LineNumber: line.LineNumber,
+			Words:      make([]model.WordSyllables, 0, len(words)),
+		}
+
+		for _, word := range words {
+			count := a.syllables.CountInLanguage(word, lineLanguage(line, input.Language))
+			lineFlow.Syllables += count
+			lineFlow.Words = append(lineFlow.Words, model.WordSyllables{Text: word, Syllables: count})
+		}
+
+		if line.StartMs != nil && line.EndMs != nil && *line.EndMs > *line.StartMs {
+			count := timedCount{
+				lineNumber: line.LineNumber,
+				startMs:    *line.StartMs,
+				endMs:      *line.EndMs,
+				syllables:  lineFlow.Syllables,
+				words:      len(words),
+			}
+			lineFlow.SyllablesPerSecond, lineFlow.WordsPerSecond = deliveryRates([]timedCount{count})
+			timed = append(timed, count)
+		}
+
+		flow.TotalSyllables += lineFlow.Syllables
+		flow.Lines = append(flow.Lines, lineFlow)
+	}
+
+	if len(flow.Lines) > 0 {
+		flow.AverageSyllablesPerLine = round2(float64(flow.TotalSyllables) / float64(len(flow.Lines)))
+	}
+
+	flow.SyllablesPerSecond, flow.WordsPerSecond = deliveryRates(timed)
+	flow.Sections = sectionFlows(analysisSections(input, response), flow.Lines, timed)
+	flow.Timeline = flowTimeline(timed)
+
+	response.Flow = flow
+	return nil
+}
+
+// deliveryRates returns syllables and words per second over the time the
+// given lines are sung, so instrumental gaps don't slow the rate down
+func deliveryRates(counts []timedCount) (float64, float64) {
+	durationMs, syllables, words := 0, 0, 0
+	for _, count := range counts {
+		durationMs += count.endMs - count.startMs
+		syllables += count.syllables
+		words += count.words
+	}
+
+	if durationMs == 0 {
+		return 0, 0
+	}
+
+	seconds := float64(durationMs) / 1000
+	return round2(float64(syllables) / seconds), round2(float64(words) / seconds)
+}
+
+// sectionFlows totals syllables and delivery rates per section
+func sectionFlows(sections []model.Section, lines []model.LineFlow, timed []timedCount) []model.SectionFlow {
+	var flows []model.SectionFlow
+
+	for _, section := range sections {
+		sectionFlow := model.SectionFlow{
+			Label:     section.Label,
+			StartLine: section.StartLine,
+			EndLine:   section.EndLine,
+		}
+
+		for _, line := range lines {
+			if line.LineNumber >= section.StartLine && line.LineNumber <= section.EndLine {
+				sectionFlow.Syllables += line.Syllables
+			}
+		}
+
+		var inSection []timedCount
+		for _, count := range timed {
+			if count.lineNumber >= section.StartLine && count.lineNumber <= section.EndLine {
+				inSection = append(inSection, count)
+			}
+		}
+		sectionFlow.SyllablesPerSecond, sectionFlow.WordsPerSecond = deliveryRates(inSection)
+
+		flows = append(flows, sectionFlow)
+	}
+
+	return flows
+}
+
+// flowTimeline spreads each line's syllables and words evenly over its
+// duration and reports the density of every fixed window of the song
+func flowTimeline(timed []timedCount) []model.FlowPoint {
+	songEnd := 0
+	for _, count := range timed {
+		songEnd = max(songEnd, count.endMs)
+	}
+
+	var points []model.FlowPoint
+	for windowStart := 0; windowStart < songEnd; windowStart += flowWindowMs {
+		windowEnd := min(windowStart+flowWindowMs, songEnd)
+		syllables, words := 0.0, 0.0
+
+		for _, count := range timed {
+			overlap := min(count.endMs, windowEnd) - max(count.startMs, windowStart)
+			if overlap <= 0 {
+				continue
+			}
+			share := float64(overlap) / float64(count.endMs-count.startMs)
+			syllables += share * float64(count.syllables)
+			words += share * float64(count.words)
+		}
+
+		seconds := float64(windowEnd-windowStart) / 1000
+		points = append(points, model.FlowPoint{
+			StartMs:            windowStart,
+			EndMs:              windowEnd,
+			SyllablesPerSecond: round2(syllables / seconds),
+			WordsPerSecond:     round2(words / seconds),
+		})
+	}
+
+	return points
+}
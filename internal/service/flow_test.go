@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowAnalyzer_Analyze_Synced(t *testing.T) {
+	parser := NewParser()
+	lines, err := parser.ParseSyncedLyrics(`[00:00.00] Beautiful night
+[00:04.00] Dancing
+[00:06.00]
+[00:10.00] Go go go go go go go go
+[00:12.00] Slow`)
+	require.NoError(t, err)
+	assignEndTimes(lines, 20000)
+
+	input := &AnalysisInput{Lines: lines, Synced: true}
+	response := &model.SongAnalysisResponse{}
+
+	err = NewFlowAnalyzer().Analyze(context.Background(), input, response)
+	require.NoError(t, err)
+
+	flow := response.Flow
+	assert.Equal(t, 15, flow.TotalSyllables)
+	assert.Equal(t, 3.75, flow.AverageSyllablesPerLine)
+
+	// Lines: 4 syllables over 4s, 2 over 2s, 8 over 2s, 1 over 8s (breaks excluded)
+	assert.Equal(t, []model.WordSyllables{{Text: "Beautiful", Syllables: 3}, {Text: "night", Syllables: 1}}, flow.Lines[0].Words)
+	assert.Equal(t, 1.0, flow.Lines[0].SyllablesPerSecond)
+	assert.Equal(t, 0.5, flow.Lines[0].WordsPerSecond)
+	assert.Equal(t, 4.0, flow.Lines[2].SyllablesPerSecond)
+	assert.Equal(t, 0.94, flow.SyllablesPerSecond)
+	assert.Equal(t, 0.75, flow.WordsPerSecond)
+
+	// Sections fall back to stanzas split at the break
+	require.Len(t, flow.Sections, 2)
+	assert.Equal(t, 6, flow.Sections[0].Syllables)
+	assert.Equal(t, 1.0, flow.Sections[0].SyllablesPerSecond)
+	assert.Equal(t, 9, flow.Sections[1].Syllables)
+	assert.Equal(t, 0.9, flow.Sections[1].SyllablesPerSecond)
+
+	// 10-second windows: lines 1-2 in the first, lines 4-5 in the second
+	assert.Equal(t, []model.FlowPoint{
+		{StartMs: 0, EndMs: 10000, SyllablesPerSecond: 0.6, WordsPerSecond: 0.3},
+		{StartMs: 10000, EndMs: 20000, SyllablesPerSecond: 0.9, WordsPerSecond: 0.9},
+	}, flow.Timeline)
+}
+
+func TestFlowAnalyzer_Analyze_Plain(t *testing.T) {
+	input := &AnalysisInput{Lines: songLines("Hello there", "Everything is fine")}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewFlowAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	assert.Equal(t, 8, response.Flow.TotalSyllables)
+	assert.Zero(t, response.Flow.SyllablesPerSecond)
+	assert.Nil(t, response.Flow.Timeline)
+	assert.Zero(t, response.Flow.Lines[0].SyllablesPerSecond)
+	assert.False(t, response.Flow.Approximate)
+}
+
+func TestFlowAnalyzer_Analyze_NotEnglish(t *testing.T) {
+	input := &AnalysisInput{Lines: songLines("Fire fire"), Language: "es"}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewFlowAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	assert.True(t, response.Flow.Approximate)
+	assert.Equal(t, 2, response.Flow.TotalSyllables)
+}
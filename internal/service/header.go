@@ -0,0 +1,87 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// lengthToleranceSeconds is how far [length:] may drift from the track duration before warning
+const lengthToleranceSeconds = 2
+
+// ParseHeader extracts LRC ID tags ([ar:], [ti:], [al:], [by:], [length:], [offset:]).
+// Returns nil if the lyrics contain no recognised header tags.
+func (p *Parser) ParseHeader(syncedLyrics string) *model.LRCHeader {
+	header := &model.LRCHeader{}
+	found := false
+
+	for _, line := range strings.Split(syncedLyrics, "\n") {
+		if p.applyHeaderTag(header, strings.TrimSpace(line)) {
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return header
+}
+
+// applyHeaderTag sets the header field for a single ID tag line.
+// Returns false if the line is not a valid recognised tag.
+func (p *Parser) applyHeaderTag(header *model.LRCHeader, line string) bool {
+	matches := p.headerRegex.FindStringSubmatch(line)
+	if len(matches) != 3 {
+		return false
+	}
+
+	value := strings.TrimSpace(matches[2])
+
+	switch strings.ToLower(matches[1]) {
+	case "ar":
+		header.Artist = value
+	case "ti":
+		header.Title = value
+	case "al":
+		header.Album = value
+	case "by":
+		header.Author = value
+	case "length":
+		seconds, err := p.parseLength(value)
+		if err != nil {
+			return false
+		}
+		header.Length = value
+		header.LengthSeconds = seconds
+	case "offset":
+		offset, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+		if err != nil {
+			return false
+		}
+		header.OffsetMs = offset
+	}
+
+	return true
+}
+
+// parseLength converts a [length:] value (mm:ss or mm:ss.xx) to whole seconds
+func (p *Parser) parseLength(value string) (int, error) {
+	seconds, err := p.ParseTimestamp(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(seconds + 0.5), nil
+}
+
+// applyOffset shifts a timestamp by the LRC [offset:] value.
+// A positive offset makes lyrics appear sooner; results are clamped at zero.
+func applyOffset(ms, offsetMs int) int {
+	shifted := ms - offsetMs
+	if shifted < 0 {
+		return 0
+	}
+	return shifted
+}
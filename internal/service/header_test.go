@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *model.LRCHeader
+	}{
+		{
+			name: "all supported tags",
+			input: `[ar:Rick Astley]
+[ti:Never Gonna Give You Up]
+[al:Whenever You Need Somebody]
+[by:lrc-maker]
+[length:03:33]
+[offset:+250]
+[00:18.00] We're no strangers to love`,
+			expected: &model.LRCHeader{
+				Artist:        "Rick Astley",
+				Title:         "Never Gonna Give You Up",
+				Album:         "Whenever You Need Somebody",
+				Author:        "lrc-maker",
+				Length:        "03:33",
+				LengthSeconds: 213,
+				OffsetMs:      250,
+			},
+		},
+		{
+			name:     "negative offset",
+			input:    "[offset:-500]\n[00:01.00] Line",
+			expected: &model.LRCHeader{OffsetMs: -500},
+		},
+		{
+			name:     "tag names are case-insensitive",
+			input:    "[AR: Artist ]\n[00:01.00] Line",
+			expected: &model.LRCHeader{Artist: "Artist"},
+		},
+		{
+			name:     "invalid offset ignored",
+			input:    "[offset:soon]\n[00:01.00] Line",
+			expected: nil,
+		},
+		{
+			name:     "no header tags",
+			input:    "[00:01.00] Line",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			assert.Equal(t, tt.expected, parser.ParseHeader(tt.input))
+		})
+	}
+}
+
+func TestParser_ParseSyncedLyrics_Offset(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		timestamps []string
+		firstWord  model.Word
+	}{
+		{
+			name:       "positive offset shows lyrics sooner",
+			input:      "[offset:+500]\n[00:10.00]<00:10.00>Hello <00:10.50>world\n[01:00.25] Next",
+			timestamps: []string{"00:09.50", "00:59.75"},
+			firstWord:  model.Word{Text: "Hello", StartMs: 9500, EndMs: 10000},
+		},
+		{
+			name:       "negative offset delays lyrics",
+			input:      "[offset:-1000]\n[00:10.00]<00:10.00>Hello <00:10.50>world\n[00:59.50] Next",
+			timestamps: []string{"00:11.00", "01:00.50"},
+			firstWord:  model.Word{Text: "Hello", StartMs: 11000, EndMs: 11500},
+		},
+		{
+			name:       "offset clamps at zero",
+			input:      "[offset:2000]\n[00:01.00]<00:01.00>Hello <00:01.50>world\n[00:05.00] Next",
+			timestamps: []string{"00:00.00", "00:03.00"},
+			firstWord:  model.Word{Text: "Hello", StartMs: 0, EndMs: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			lines, err := parser.ParseSyncedLyrics(tt.input)
+
+			assert.NoError(t, err)
+			assert.Len(t, lines, len(tt.timestamps))
+			for i, expected := range tt.timestamps {
+				assert.Equal(t, expected, *lines[i].Timestamp)
+			}
+			assert.Equal(t, tt.firstWord, lines[0].Words[0])
+		})
+	}
+}
+
+func TestCheckLengthMismatch(t *testing.T) {
+	header := &model.LRCHeader{Length: "03:33", LengthSeconds: 213}
+
+	assert.Empty(t, checkLengthMismatch(header, 212), "within tolerance")
+	assert.Empty(t, checkLengthMismatch(nil, 212), "no header")
+	assert.Empty(t, checkLengthMismatch(header, 0), "unknown duration")
+	assert.Equal(t, "LRC length tag (03:33) does not match track duration (180s)", checkLengthMismatch(header, 180))
+}
@@ -1,12 +1,53 @@
 package service
 
-import "github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+import (
+	"context"
+	"io"
 
-// LyricsParser defines the interface for parsing lyrics
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// LyricsParser parses the lyrics a provider returns. It holds only what
+// the service needs, so any parser can be plugged in; the concrete Parser
+// offers more entry points, such as string and streaming variants.
 type LyricsParser interface {
-	// ParseSyncedLyrics parses synced lyrics with timestamps into structured format
-	ParseSyncedLyrics(syncedLyrics string) ([]model.LyricLine, error)
+	// ParseTimedLyricsReader detects whether timed lyrics are LRC, SRT or
+	// WebVTT and parses them from a reader, returning the detected format
+	ParseTimedLyricsReader(ctx context.Context, r io.Reader) ([]model.LyricLine, string, []model.Diagnostic, error)
+
+	// ParsePlainLyricsReader parses plain lyrics and their section headers from a reader
+	ParsePlainLyricsReader(ctx context.Context, r io.Reader) ([]model.LyricLine, []model.Section, error)
+
+	// ParseHeader extracts LRC ID tags such as [ar:], [ti:] and [offset:]
+	ParseHeader(syncedLyrics string) *model.LRCHeader
+
+	// Tokenizer returns the tokenizer used to count words
+	Tokenizer() Tokenizer
+}
+
+// Analyzer adds one named section to a song analysis. Analyzers run in the
+// order they are given to the service, so later ones can build on the
+// output of earlier ones.
+type Analyzer interface {
+	// Name identifies the section the analyzer fills, such as "structure"
+	Name() string
+
+	// Analyze inspects the parsed song and fills its section of the response
+	Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error
+}
+
+// SongAnalyzer analyzes songs on behalf of the HTTP handlers. Decorators such
+// as caches or metrics can wrap it without changing the handlers.
+type SongAnalyzer interface {
+	// AnalyzeSong fetches, parses and analyzes the lyrics of a song
+	AnalyzeSong(ctx context.Context, track, artist string, opts AnalyzeOptions) (*model.SongAnalysisResponse, error)
+}
 
-	// ParsePlainLyrics parses plain lyrics without timestamps
-	ParsePlainLyrics(plainLyrics string) ([]model.LyricLine, error)
+// TextMasker hides explicit words in lyric text for clean edits. An
+// analyzer that implements it masks the response when a clean edit is
+// requested, even if its own section is excluded.
+type TextMasker interface {
+	// MaskText returns text with each explicit word replaced by asterisks.
+	// Language is the text's ISO 639-1 code, or empty when unknown.
+	MaskText(text, language string) string
 }
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"embed"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// minLanguageLetters is the fewest letters a line needs before its language
+// is guessed; shorter lines such as "Oh, oh" are left untagged
+const minLanguageLetters = 6
+
+// syllabicLetterWeight is how many letters a Han, kana or Hangul character
+// counts as, since each one spells a whole syllable
+const syllabicLetterWeight = 3
+
+// languageSamples holds sample text for each Latin-script language the
+// detector can tell apart, one file per ISO 639-1 code
+//
+//go:embed data/languages/*.txt
+var languageSamples embed.FS
+
+// stopwordLists holds the stopwords of each language, one file per ISO 639-1 code
+//
+//go:embed data/stopwords/*.txt
+var stopwordLists embed.FS
+
+// defaultNgramProfiles builds the trigram profiles from the bundled samples once on first use
+var defaultNgramProfiles = sync.OnceValue(func() *ngramProfiles {
+	return buildNgramProfiles(readLanguageFiles(languageSamples, "data/languages"))
+})
+
+// defaultStopwords loads the bundled stopword lists once on first use
+var defaultStopwords = sync.OnceValue(func() map[string]map[string]struct{} {
+	lists := make(map[string]map[string]struct{})
+	for code, text := range readLanguageFiles(stopwordLists, "data/stopwords") {
+		words := make(map[string]struct{})
+		for _, word := range strings.Fields(text) {
+			words[word] = struct{}{}
+		}
+		lists[code] = words
+	}
+	return lists
+})
+
+// scriptLanguages maps scripts that identify a language on their own to its
+// code. Japanese text that mixes kana with Han characters is told apart
+// from Chinese by the kana.
+var scriptLanguages = []struct {
+	script *unicode.RangeTable
+	code   string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// spacelessLanguages are written without spaces between words
+var spacelessLanguages = []string{"ja", "th", "zh"}
+
+// ngramProfiles holds the log probability of each character trigram per language
+type ngramProfiles struct {
+	codes    []string
+	logProbs map[string]map[string]float64
+	unseen   map[string]float64 // log probability of a trigram missing from a profile
+}
+
+// buildNgramProfiles counts the trigrams of each sample text, with add-one
+// smoothing over the trigrams seen in any language
+func buildNgramProfiles(samples map[string]string) *ngramProfiles {
+	profiles := &ngramProfiles{
+		logProbs: make(map[string]map[string]float64),
+		unseen:   make(map[string]float64),
+	}
+
+	counts := make(map[string]map[string]int)
+	vocabulary := make(map[string]struct{})
+	for code, text := range samples {
+		counts[code] = make(map[string]int)
+		for _, trigram := range trigrams(text) {
+			counts[code][trigram]++
+			vocabulary[trigram] = struct{}{}
+		}
+		profiles.codes = append(profiles.codes, code)
+	}
+	slices.Sort(profiles.codes)
+
+	for code, trigramCounts := range counts {
+		total := 0
+		for _, count := range trigramCounts {
+			total += count
+		}
+
+		denominator := float64(total + len(vocabulary) + 1)
+		profiles.logProbs[code] = make(map[string]float64, len(trigramCounts))
+		for trigram, count := range trigramCounts {
+			profiles.logProbs[code][trigram] = math.Log(float64(count+1) / denominator)
+		}
+		profiles.unseen[code] = math.Log(1 / denominator)
+	}
+
+	return profiles
+}
+
+// classify returns the most likely language of text and its posterior
+// probability, treating every language as equally likely beforehand
+func (p *ngramProfiles) classify(text string) (string, float64) {
+	grams := trigrams(text)
+	if len(grams) == 0 || len(p.codes) == 0 {
+		return "", 0
+	}
+
+	scores := make([]float64, len(p.codes))
+	best := 0
+	for i, code := range p.codes {
+		for _, trigram := range grams {
+			if logProb, ok := p.logProbs[code][trigram]; ok {
+				scores[i] += logProb
+			} else {
+				scores[i] += p.unseen[code]
+			}
+		}
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+
+	// Normalize against the best score so the exponentials don't underflow
+	total := 0.0
+	for _, score := range scores {
+		total += math.Exp(score - scores[best])
+	}
+
+	return p.codes[best], 1 / total
+}
+
+// trigrams returns the lowercase character trigrams of each word in text,
+// padded with spaces so word starts and ends count: "love" gives " lo",
+// "lov", "ove" and "ve "
+func trigrams(text string) []string {
+	var grams []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+
+	return grams
+}
+
+// readLanguageFiles reads every "<code>.txt" file in dir
+func readLanguageFiles(files embed.FS, dir string) map[string]string {
+	entries, err := files.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	texts := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		texts[strings.TrimSuffix(entry.Name(), ".txt")] = string(data)
+	}
+	return texts
+}
+
+// LanguageDetector identifies the language of lyric lines offline. Scripts
+// used by a single language, such as Hangul, decide on their own; Latin
+// script is classified by character trigrams.
+type LanguageDetector struct {
+	profiles *ngramProfiles
+}
+
+// NewLanguageDetector creates a language detector using the bundled samples
+func NewLanguageDetector() *LanguageDetector {
+	return &LanguageDetector{
+		profiles: defaultNgramProfiles(),
+	}
+}
+
+// Detect tags each lyric line with its language and returns the song's main
+// language: the one covering the most letters, weighting syllabic
+// characters. Confidence weighs each
+// line's share of the song by how sure its tag is, so a mixed or unclear
+// song scores lower. Returns nil when no line is long enough to tell.
+func (d *LanguageDetector) Detect(lines []model.LyricLine) *model.Language {
+	letters := make(map[string]int)
+	weights := make(map[string]float64)
+	total := 0
+
+	for i, line := range lines {
+		if line.IsBreak || line.Text == "" {
+			continue
+		}
+
+		code, confidence := d.DetectLine(line.Text)
+		if code == "" {
+			continue
+		}
+
+		lines[i].Language = code
+		n := letterWeight(line.Text)
+		letters[code] += n
+		weights[code] += float64(n) * confidence
+		total += n
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	main := ""
+	for code, n := range letters {
+		if n > letters[main] || (n == letters[main] && code < main) {
+			main = code
+		}
+	}
+
+	language := &model.Language{
+		Code:       main,
+		Confidence: round2(weights[main] / float64(total)),
+	}
+
+	// Shares only add information when the song switches language
+	if len(letters) > 1 {
+		language.Shares = make(map[string]float64, len(letters))
+		for code, n := range letters {
+			language.Shares[code] = round2(float64(n) / float64(total))
+		}
+	}
+
+	return language
+}
+
+// DetectLine returns the language of a single line and how confident the
+// guess is, from 0 to 1. Returns "" when the line is too short to tell or
+// its script is not supported.
+func (d *LanguageDetector) DetectLine(text string) (string, float64) {
+	scripts := make(map[string]int)
+	latin, total := 0, 0
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		total++
+
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.script, r) {
+				scripts[s.code]++
+				break
+			}
+		}
+	}
+
+	if letterWeight(text) < minLanguageLetters {
+		return "", 0
+	}
+
+	// Han characters in text with kana are Japanese kanji
+	if scripts["ja"] > 0 {
+		scripts["ja"] += scripts["zh"]
+		delete(scripts, "zh")
+	}
+
+	best := ""
+	for code, n := range scripts {
+		if n > scripts[best] || (n == scripts[best] && code < best) {
+			best = code
+		}
+	}
+
+	switch {
+	case latin > 0 && latin >= scripts[best]:
+		code, posterior := d.profiles.classify(text)
+		return code, round2(posterior * float64(latin) / float64(total))
+	case best != "":
+		return best, round2(float64(scripts[best]) / float64(total))
+	default:
+		return "", 0
+	}
+}
+
+// knownLanguage reports whether the detector can report the given code
+func (d *LanguageDetector) knownLanguage(code string) bool {
+	if slices.Contains(d.profiles.codes, code) {
+		return true
+	}
+	for _, s := range scriptLanguages {
+		if s.code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// letterWeight counts the letters in text, weighting syllabic characters
+func letterWeight(text string) int {
+	n := 0
+	for _, r := range text {
+		switch {
+		case isSyllabicScript(r):
+			n += syllabicLetterWeight
+		case unicode.IsLetter(r):
+			n++
+		}
+	}
+	return n
+}
+
+// englishDataLanguage is the language of the bundled pronunciation
+// dictionary and sentiment lexicon
+const englishDataLanguage = "en"
+
+// outsideEnglishData reports whether text in the given language falls
+// outside the bundled English data. Text of unknown language is taken as
+// English.
+func outsideEnglishData(language string) bool {
+	return language != "" && language != englishDataLanguage
+}
+
+// lineLanguage returns a line's language, or the song's when the line is
+// untagged
+func lineLanguage(line model.LyricLine, song string) string {
+	if line.Language != "" {
+		return line.Language
+	}
+	return song
+}
+
+// englishDataApproximate reports whether analyzers built on the English
+// data only partly apply to a song: its language or that of any lyric line
+// is another one. Every such analyzer marks its result approximate alike.
+func englishDataApproximate(input *AnalysisInput) bool {
+	for _, line := range input.Lines {
+		if !line.IsBreak && line.Text != "" && outsideEnglishData(lineLanguage(line, input.Language)) {
+			return true
+		}
+	}
+	return false
+}
+
+// languageSupport holds the language-specific tools analyzers pick by the
+// song's language
+type languageSupport struct {
+	tokenizer Tokenizer
+	stopwords map[string]struct{}
+	stem      func(word string) string
+}
+
+// supportFor returns the tools for a language. Space-delimited languages
+// are split on whitespace and spaceless or unknown ones by script; only
+// English has a stemmer, other languages compare whole words.
+func supportFor(code string) languageSupport {
+	support := languageSupport{
+		tokenizer: NewUnicodeTokenizer(),
+		stopwords: defaultStopwords()[code],
+		stem:      func(word string) string { return word },
+	}
+
+	if code != "" && !slices.Contains(spacelessLanguages, code) {
+		support.tokenizer = NewWhitespaceTokenizer()
+	}
+	if code == "en" {
+		support.stem = porterStem
+	}
+
+	return support
+}
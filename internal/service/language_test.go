@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageDetector_DetectLine(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "Hello from the other side", "en"},
+		{"spanish", "Bailando bajo la luna", "es"},
+		{"portuguese", "Eu sei que vou te amar", "pt"},
+		{"french", "Je ne regrette rien", "fr"},
+		{"german", "Ich will dich sehen", "de"},
+		{"italian", "Ti amo, non lasciarmi mai", "it"},
+		{"korean", "사랑해 너를", "ko"},
+		{"japanese kanji with kana", "君の名前を呼んだ", "ja"},
+		{"chinese", "我爱你", "zh"},
+		{"russian", "Все будет хорошо", "ru"},
+		{"too short", "Oh oh", ""},
+		{"unsupported script", "Սիրում եմ քեզ", ""},
+	}
+
+	detector := NewLanguageDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := detector.DetectLine(tt.text)
+			assert.Equal(t, tt.want, got)
+			if tt.want == "" {
+				assert.Zero(t, confidence)
+			} else {
+				assert.Greater(t, confidence, 0.5)
+			}
+		})
+	}
+}
+
+func TestLanguageDetector_Detect(t *testing.T) {
+	detector := NewLanguageDetector()
+
+	t.Run("single language", func(t *testing.T) {
+		lines := songLines("Hello from the other side", "I must have called a thousand times", "", "Oh oh")
+
+		language := detector.Detect(lines)
+
+		require.NotNil(t, language)
+		assert.Equal(t, "en", language.Code)
+		assert.Greater(t, language.Confidence, 0.9)
+		assert.Nil(t, language.Shares)
+		assert.Equal(t, "en", lines[1].Language)
+		assert.Empty(t, lines[2].Language)
+		assert.Empty(t, lines[3].Language)
+	})
+
+	t.Run("code-switched song", func(t *testing.T) {
+		lines := songLines("사랑해 너를 정말로", "Baby I love you", "너만 보여")
+
+		language := detector.Detect(lines)
+
+		require.NotNil(t, language)
+		assert.Equal(t, "ko", language.Code)
+		assert.Equal(t, map[string]float64{"ko": 0.75, "en": 0.25}, language.Shares)
+		assert.Less(t, language.Confidence, 0.8)
+	})
+
+	t.Run("nothing to detect", func(t *testing.T) {
+		assert.Nil(t, detector.Detect([]model.LyricLine{{LineNumber: 1, Text: "Oh"}}))
+	})
+}
+
+func TestSupportFor(t *testing.T) {
+	english := supportFor("en")
+	assert.Equal(t, TokenizerWhitespace, english.tokenizer.Name())
+	assert.Contains(t, english.stopwords, "the")
+	assert.Equal(t, "danc", english.stem("dancing"))
+
+	spanish := supportFor("es")
+	assert.Contains(t, spanish.stopwords, "que")
+	assert.Equal(t, "bailando", spanish.stem("bailando"))
+
+	japanese := supportFor("ja")
+	assert.Equal(t, TokenizerUnicode, japanese.tokenizer.Name())
+	assert.Nil(t, japanese.stopwords)
+
+	unknown := supportFor("")
+	assert.Equal(t, TokenizerUnicode, unknown.tokenizer.Name())
+}
+
+func TestEnglishDataApproximate(t *testing.T) {
+	tagged := func(language string, texts ...string) []model.LyricLine {
+		lines := songLines(texts...)
+		for i := range lines {
+			if lines[i].Text != "" {
+				lines[i].Language = language
+			}
+		}
+		return lines
+	}
+	mixed := songLines("I love you", "Te quiero")
+	mixed[1].Language = "es"
+
+	tests := []struct {
+		name  string
+		input *AnalysisInput
+		want  bool
+	}{
+		{"english song", &AnalysisInput{Lines: tagged("en", "I love you"), Language: "en"}, false},
+		{"unknown language", &AnalysisInput{Lines: songLines("I love you")}, false},
+		{"song in another language", &AnalysisInput{Lines: songLines("Te quiero"), Language: "es"}, true},
+		{"english song with a spanish line", &AnalysisInput{Lines: mixed, Language: "en"}, true},
+		{"no lyric lines", &AnalysisInput{Lines: songLines(""), Language: "es"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, englishDataApproximate(tt.input))
+		})
+	}
+}
+
+func TestEnglishDataAnalyzers_AgreeOnApproximate(t *testing.T) {
+	lines := songLines("I see the light", "Bailando bajo la luna")
+	lines[1].Language = "es"
+	input := &AnalysisInput{Lines: lines, Language: "en"}
+	response := &model.SongAnalysisResponse{}
+
+	require.NoError(t, NewRhymeAnalyzer().Analyze(context.Background(), input, response))
+	require.NoError(t, NewFlowAnalyzer().Analyze(context.Background(), input, response))
+	require.NoError(t, NewSentimentAnalyzer().Analyze(context.Background(), input, response))
+
+	assert.True(t, response.Rhyme.Approximate)
+	assert.True(t, response.Flow.Approximate)
+	assert.True(t, response.Sentiment.Approximate)
+}
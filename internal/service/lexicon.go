@@ -0,0 +1,81 @@
+package service
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bundledSentiment is a VADER-style valence lexicon with NRC-style emotions
+//
+//go:embed data/sentiment.lex
+var bundledSentiment string
+
+// defaultSentimentLexicon parses the bundled lexicon once on first use
+var defaultSentimentLexicon = sync.OnceValue(func() *sentimentLexicon {
+	return parseSentimentLexicon(bundledSentiment)
+})
+
+// sentimentEntry is the valence and emotions of one word
+type sentimentEntry struct {
+	valence  float64
+	emotions []string
+}
+
+// sentimentLexicon maps lowercase words, and their stems, to sentiment entries
+type sentimentLexicon struct {
+	entries map[string]sentimentEntry
+	stems   map[string]sentimentEntry
+}
+
+// parseSentimentLexicon reads lines of "word valence [emotion,emotion]",
+// skipping # comments and lines whose valence is not a number
+func parseSentimentLexicon(data string) *sentimentLexicon {
+	lexicon := &sentimentLexicon{
+		entries: make(map[string]sentimentEntry),
+		stems:   make(map[string]sentimentEntry),
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		valence, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+
+		entry := sentimentEntry{valence: valence}
+		if len(fields) > 2 {
+			entry.emotions = strings.Split(fields[2], ",")
+		}
+
+		word := strings.ToLower(fields[0])
+		lexicon.entries[word] = entry
+
+		// The first word listed for a stem wins
+		stem := porterStem(word)
+		if _, ok := lexicon.stems[stem]; !ok {
+			lexicon.stems[stem] = entry
+		}
+	}
+
+	return lexicon
+}
+
+// lookup returns the entry for word, trying its stem when the word itself
+// is not listed so "loving" scores like "love"
+func (l *sentimentLexicon) lookup(word string) (sentimentEntry, bool) {
+	if entry, ok := l.entries[word]; ok {
+		return entry, true
+	}
+	entry, ok := l.stems[porterStem(word)]
+	return entry, ok
+}
@@ -3,35 +3,68 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
 )
 
-// LyricsService orchestrates lyrics analysis
+// LyricsService orchestrates lyrics analysis and implements SongAnalyzer
 type LyricsService struct {
-	lyricsProvider LyricsProvider
-	parser         *Parser
-	chorusDetector *ChorusDetector
+	lyricsProvider   LyricsProvider
+	parser           LyricsParser
+	cleaner          *LineCleaner
+	languageDetector *LanguageDetector
+	analyzers        []Analyzer
 }
 
-// NewLyricsService creates a new lyrics service
+// NewLyricsService creates a new lyrics service that removes junk lines
+// with the built-in rules. Analyzers run in the given order, each adding
+// its section to the response.
 func NewLyricsService(
 	lyricsProvider LyricsProvider,
-	parser *Parser,
-	chorusDetector *ChorusDetector,
+	parser LyricsParser,
+	analyzers ...Analyzer,
+) *LyricsService {
+	return NewLyricsServiceWithCleaner(lyricsProvider, parser, NewLineCleaner(), analyzers...)
+}
+
+// NewLyricsServiceWithCleaner creates a new lyrics service that removes
+// junk lines with the given cleaner; a nil cleaner keeps every line
+func NewLyricsServiceWithCleaner(
+	lyricsProvider LyricsProvider,
+	parser LyricsParser,
+	cleaner *LineCleaner,
+	analyzers ...Analyzer,
 ) *LyricsService {
 	return &LyricsService{
-		lyricsProvider: lyricsProvider,
-		parser:         parser,
-		chorusDetector: chorusDetector,
+		lyricsProvider:   lyricsProvider,
+		parser:           parser,
+		cleaner:          cleaner,
+		languageDetector: NewLanguageDetector(),
+		analyzers:        analyzers,
 	}
 }
 
+// parsedLyrics holds the result of parsing the provider's lyrics
+type parsedLyrics struct {
+	lines         []model.LyricLine
+	lyricsType    string
+	format        string
+	hasTimestamps bool
+	diagnostics   []model.Diagnostic
+	sections      []model.Section
+}
+
 // AnalyzeSong performs complete song analysis
-func (ls *LyricsService) AnalyzeSong(ctx context.Context, track, artist string) (*model.SongAnalysisResponse, error) {
+func (ls *LyricsService) AnalyzeSong(ctx context.Context, track, artist string, opts AnalyzeOptions) (*model.SongAnalysisResponse, error) {
 	startTime := time.Now()
 
+	// Reject unknown sections and languages before doing any work
+	if err := opts.validate(ls.analyzers, ls.languageDetector); err != nil {
+		return nil, err
+	}
+
 	// Fetch lyrics from LRCLib API
 	lyricsData, err := ls.lyricsProvider.GetLyrics(ctx, track, artist)
 	if err != nil {
@@ -50,101 +83,333 @@ func (ls *LyricsService) AnalyzeSong(ctx context.Context, track, artist string)
 
 	// If instrumental, return early (no lyrics to analyze)
 	if lyricsData.Instrumental {
-		processingTime := time.Since(startTime).Milliseconds()
-		return &model.SongAnalysisResponse{
-			Track: trackInfo,
-			Metadata: model.Metadata{
-				Source:           model.SourceLRCLib,
-				Cached:           false,
-				ProcessingTimeMs: processingTime,
-				Timestamp:        time.Now(),
-				Message:          "Instrumental track - no lyrics available",
-			},
-		}, nil
+		return instrumentalResponse(trackInfo, startTime, nil), nil
 	}
 
 	// Parse lyrics (prefer synced over plain)
-	lines, lyricsType, hasTimestamps, err := ls.parseLyrics(lyricsData)
+	parsed, err := ls.parseLyrics(ctx, lyricsData)
 	if err != nil {
 		return nil, err
 	}
 
 	// No lyrics available
-	if lines == nil {
-		processingTime := time.Since(startTime).Milliseconds()
-		return &model.SongAnalysisResponse{
-			Track: trackInfo,
-			Metadata: model.Metadata{
-				Source:           model.SourceLRCLib,
-				Cached:           false,
-				ProcessingTimeMs: processingTime,
-				Timestamp:        time.Now(),
-				Message:          "No lyrics available for this track",
-			},
-		}, nil
+	if parsed == nil {
+		return noLyricsResponse(trackInfo, startTime, nil), nil
+	}
+
+	var warnings []string
+
+	// Diagnostics and cleaner warnings quote source lines, so they are masked too
+	masker := findTextMasker(ls.analyzers)
+	if opts.Clean {
+		maskDiagnostics(parsed.diagnostics, masker)
 	}
 
+	// Credits, site addresses and placeholders are not lyrics
+	if ls.cleaner != nil {
+		cleaned := ls.cleaner.clean(parsed.lines, parsed.sections)
+		parsed.lines, parsed.sections = cleaned.lines, cleaned.sections
+		if opts.Clean {
+			maskRemovedLines(cleaned.removed, masker)
+		}
+		warnings = cleaned.warnings()
+
+		// Lyrics made only of placeholders such as "♪" or an [Instrumental]
+		// header belong to an instrumental
+		if cleaned.instrumental || (len(cleaned.lines) == 0 && ls.cleaner.marksInstrumental(sourceLyrics(lyricsData))) {
+			trackInfo.Instrumental = true
+			return instrumentalResponse(trackInfo, startTime, warnings), nil
+		}
+	}
+
+	// Header tags or junk alone leave nothing to analyze, which does not make
+	// the track an instrumental
+	if len(lyricLinesOnly(parsed.lines)) == 0 {
+		warnings = append(diagnosticWarnings(parsed.diagnostics), warnings...)
+		warnings = append(warnings, "no lyric lines left after parsing and cleaning")
+		return noLyricsResponse(trackInfo, startTime, warnings), nil
+	}
+
+	lines := parsed.lines
+
 	// Build lyrics data
 	lyricsInfo := &model.LyricsData{
-		Type:          lyricsType,
-		HasTimestamps: hasTimestamps,
+		Type:          parsed.lyricsType,
+		Format:        parsed.format,
+		HasTimestamps: parsed.hasTimestamps,
+		Tokenizer:     ls.parser.Tokenizer().Name(),
 		TotalLines:    len(lines),
 		Lines:         lines,
 	}
 
-	// Detect chorus (graceful degradation - don't fail if chorus detection fails)
-	var chorus *model.Chorus
-	if ls.chorusDetector != nil {
-		chorus = ls.chorusDetector.DetectChorus(lines)
+	// Detection is skipped when neither the response nor an analyzer uses it
+	if opts.needsLanguage(ls.analyzers) {
+		lyricsInfo.Language = ls.languageDetector.Detect(lines)
 	}
 
-	// If chorus detection failed or is nil, return empty chorus result
-	if chorus == nil {
-		chorus = &model.Chorus{Detected: false}
-	}
+	warnings = append(diagnosticWarnings(parsed.diagnostics), warnings...)
 
-	structure := &model.Structure{
-		Chorus: chorus,
+	// Line timings are only present in synced lyrics
+	if parsed.lyricsType == model.LyricsTypeSynced {
+		// The last line ends with the track
+		assignEndTimes(lines, trackInfo.Duration*1000)
 	}
 
-	// Calculate processing time
-	processingTime := time.Since(startTime).Milliseconds()
+	// Header tags only exist in LRC; SRT and WebVTT have no equivalent
+	if parsed.format == model.FormatLRC {
+		lyricsInfo.Header = ls.parser.ParseHeader(lyricsData.SyncedLyrics)
+		if warning := checkLengthMismatch(lyricsInfo.Header, trackInfo.Duration); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
 
 	// Build complete response
 	response := &model.SongAnalysisResponse{
-		Track:     trackInfo,
-		Lyrics:    lyricsInfo,
-		Structure: structure,
+		Track:      trackInfo,
+		Lyrics:     lyricsInfo,
+		Extensions: make(map[string]interface{}),
+		Metadata: model.Metadata{
+			Source:    model.SourceLRCLib,
+			Cached:    false,
+			Timestamp: time.Now(),
+			Warnings:  warnings,
+		},
+	}
+
+	input := &AnalysisInput{
+		Track:    trackInfo,
+		Lines:    lines,
+		Synced:   parsed.hasTimestamps,
+		Sections: parsed.sections,
+		Options:  opts,
+	}
+	if lyricsInfo.Language != nil {
+		input.Language = lyricsInfo.Language.Code
+	}
+
+	if err := ls.runAnalyzers(ctx, input, response, opts); err != nil {
+		return nil, err
+	}
+
+	if len(response.Extensions) == 0 {
+		response.Extensions = nil
+	}
+
+	if opts.Clean {
+		maskResponse(response, masker)
+	}
+
+	// Lines are still parsed and analyzed, only left out of the response
+	if !opts.wantsLines() {
+		lyricsInfo.Lines = nil
+	} else {
+		lyricsInfo.Lines = opts.filterLanguages(lyricsInfo.Lines)
+	}
+	if !opts.wantsLyrics() {
+		response.Lyrics = nil
+	}
+
+	// Calculate processing time
+	response.Metadata.ProcessingTimeMs = time.Since(startTime).Milliseconds()
+
+	if opts.Debug {
+		response.Metadata.Diagnostics = parsed.diagnostics
+	}
+
+	return response, nil
+}
+
+// noLyricsResponse describes a track whose lyrics are missing or empty
+func noLyricsResponse(trackInfo model.Track, startTime time.Time, warnings []string) *model.SongAnalysisResponse {
+	return &model.SongAnalysisResponse{
+		Track: trackInfo,
 		Metadata: model.Metadata{
 			Source:           model.SourceLRCLib,
 			Cached:           false,
-			ProcessingTimeMs: processingTime,
+			ProcessingTimeMs: time.Since(startTime).Milliseconds(),
 			Timestamp:        time.Now(),
+			Message:          "No lyrics available for this track",
+			Warnings:         warnings,
 		},
 	}
+}
 
-	return response, nil
+// sourceLyrics returns the provider text the lyrics were parsed from
+func sourceLyrics(lyricsData *model.LyricsSourceData) string {
+	if lyricsData.SyncedLyrics != "" {
+		return lyricsData.SyncedLyrics
+	}
+	return lyricsData.PlainLyrics
 }
 
-// parseLyrics handles lyrics parsing logic, returning lines, type, and timestamp flag
-func (ls *LyricsService) parseLyrics(lyricsData *model.LyricsSourceData) ([]model.LyricLine, string, bool, error) {
-	// Prefer synced lyrics over plain
+// instrumentalResponse describes a track without lyrics to analyze
+func instrumentalResponse(trackInfo model.Track, startTime time.Time, warnings []string) *model.SongAnalysisResponse {
+	return &model.SongAnalysisResponse{
+		Track: trackInfo,
+		Metadata: model.Metadata{
+			Source:           model.SourceLRCLib,
+			Cached:           false,
+			ProcessingTimeMs: time.Since(startTime).Milliseconds(),
+			Timestamp:        time.Now(),
+			Message:          "Instrumental track - no lyrics available",
+			Warnings:         warnings,
+		},
+	}
+}
+
+// runAnalyzers runs each analyzer in order. A failing analyzer leaves its
+// section out and adds a warning instead of failing the whole analysis,
+// unless the request itself was cancelled.
+func (ls *LyricsService) runAnalyzers(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse, opts AnalyzeOptions) error {
+	for _, analyzer := range ls.analyzers {
+		// Skip sections the client did not ask for so their work is never done
+		if !opts.computes(analyzer.Name()) {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := analyzer.Analyze(ctx, input, response); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			response.Metadata.Warnings = append(response.Metadata.Warnings, fmt.Sprintf("%s analysis failed: %v", analyzer.Name(), err))
+		}
+	}
+
+	// Structure may only have run for the sections other analyzers report on
+	if !opts.wants(AnalyzerStructure) {
+		response.Structure = nil
+	}
+
+	return nil
+}
+
+// findTextMasker returns the first analyzer that can mask explicit words
+func findTextMasker(analyzers []Analyzer) TextMasker {
+	for _, analyzer := range analyzers {
+		if masker, ok := analyzer.(TextMasker); ok {
+			return masker
+		}
+	}
+	return nil
+}
+
+// maskResponse masks explicit words wherever the response quotes the
+// lyrics. Lines keep their timestamps; only their text changes.
+func maskResponse(response *model.SongAnalysisResponse, masker TextMasker) {
+	if lyrics := response.Lyrics; lyrics != nil {
+		for i := range lyrics.Lines {
+			line := &lyrics.Lines[i]
+			line.Text = masker.MaskText(line.Text, line.Language)
+			for j := range line.Words {
+				line.Words[j].Text = masker.MaskText(line.Words[j].Text, line.Language)
+			}
+		}
+	}
+
+	if response.Structure != nil && response.Structure.Chorus != nil {
+		response.Structure.Chorus.Text = masker.MaskText(response.Structure.Chorus.Text, "")
+	}
+
+	if response.Statistics != nil && response.Statistics.LongestLine != nil {
+		response.Statistics.LongestLine.Text = masker.MaskText(response.Statistics.LongestLine.Text, "")
+	}
+
+	if response.Flow != nil {
+		for i := range response.Flow.Lines {
+			for j := range response.Flow.Lines[i].Words {
+				word := &response.Flow.Lines[i].Words[j]
+				word.Text = masker.MaskText(word.Text, "")
+			}
+		}
+	}
+
+	if response.Profanity != nil {
+		for i := range response.Profanity.Lines {
+			for j := range response.Profanity.Lines[i].Words {
+				word := &response.Profanity.Lines[i].Words[j]
+				word.Text = masker.MaskText(word.Text, "")
+				word.Match = masker.MaskText(word.Match, "")
+			}
+		}
+	}
+}
+
+// maskDiagnostics masks explicit words in the raw source lines diagnostics quote
+func maskDiagnostics(diagnostics []model.Diagnostic, masker TextMasker) {
+	for i := range diagnostics {
+		diagnostics[i].Raw = masker.MaskText(diagnostics[i].Raw, "")
+	}
+}
+
+// maskRemovedLines masks explicit words in the lines the cleaner removed,
+// which its warnings quote
+func maskRemovedLines(removed []removedLine, masker TextMasker) {
+	for i := range removed {
+		line := &removed[i].line
+		line.Text = masker.MaskText(line.Text, line.Language)
+	}
+}
+
+// parseLyrics handles lyrics parsing logic, returning nil when no lyrics are available
+func (ls *LyricsService) parseLyrics(ctx context.Context, lyricsData *model.LyricsSourceData) (*parsedLyrics, error) {
+	// Prefer synced lyrics over plain; synced lyrics may be LRC, SRT or WebVTT
 	if lyricsData.SyncedLyrics != "" {
-		lines, err := ls.parser.ParseSyncedLyrics(lyricsData.SyncedLyrics)
+		lines, format, diagnostics, err := ls.parseTimedLyrics(ctx, lyricsData.SyncedLyrics)
 		if err != nil {
-			return nil, "", false, fmt.Errorf("failed to parse synced lyrics: %w", err)
+			return nil, fmt.Errorf("failed to parse synced lyrics: %w", err)
 		}
-		return lines, model.LyricsTypeSynced, true, nil
+		return &parsedLyrics{
+			lines:         lines,
+			lyricsType:    model.LyricsTypeSynced,
+			format:        format,
+			hasTimestamps: true,
+			diagnostics:   diagnostics,
+		}, nil
 	}
 
 	if lyricsData.PlainLyrics != "" {
-		lines, err := ls.parser.ParsePlainLyrics(lyricsData.PlainLyrics)
+		lines, sections, err := ls.parser.ParsePlainLyricsReader(ctx, strings.NewReader(lyricsData.PlainLyrics))
 		if err != nil {
-			return nil, "", false, fmt.Errorf("failed to parse plain lyrics: %w", err)
+			return nil, fmt.Errorf("failed to parse plain lyrics: %w", err)
 		}
-		return lines, model.LyricsTypePlain, false, nil
+		return &parsedLyrics{
+			lines:         lines,
+			lyricsType:    model.LyricsTypePlain,
+			hasTimestamps: false,
+			sections:      sections,
+		}, nil
 	}
 
 	// No lyrics available
-	return nil, "", false, nil
+	return nil, nil
+}
+
+// parseTimedLyrics parses synced lyrics in any timed format, streaming them
+// through the parser's input limits so an oversized or cancelled request
+// stops early
+func (ls *LyricsService) parseTimedLyrics(ctx context.Context, input string) ([]model.LyricLine, string, []model.Diagnostic, error) {
+	return ls.parser.ParseTimedLyricsReader(ctx, strings.NewReader(input))
+}
+
+// checkLengthMismatch compares the LRC [length:] tag with the track duration
+// and returns a warning if they differ by more than the allowed tolerance
+func checkLengthMismatch(header *model.LRCHeader, durationSeconds int) string {
+	if header == nil || header.LengthSeconds == 0 || durationSeconds == 0 {
+		return ""
+	}
+
+	diff := header.LengthSeconds - durationSeconds
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff <= lengthToleranceSeconds {
+		return ""
+	}
+
+	return fmt.Sprintf("LRC length tag (%s) does not match track duration (%ds)", header.Length, durationSeconds)
 }
@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockLyricsClient is a mock implementation of client.LyricsClient
@@ -62,6 +64,16 @@ func TestLyricsService_ParseLyrics(t *testing.T) {
 			shouldBeNil:       false,
 			expectedFirstLine: "Synced line",
 		},
+		{
+			name:              "srt in synced lyrics",
+			syncedLyrics:      "1\n00:00:10,000 --> 00:00:12,000\nSubtitle line",
+			plainLyrics:       "",
+			expectedType:      model.LyricsTypeSynced,
+			expectedTimestamp: true,
+			expectedLines:     1,
+			shouldBeNil:       false,
+			expectedFirstLine: "Subtitle line",
+		},
 		{
 			name:              "no lyrics",
 			syncedLyrics:      "",
@@ -85,20 +97,20 @@ func TestLyricsService_ParseLyrics(t *testing.T) {
 				PlainLyrics:  tt.plainLyrics,
 			}
 
-			lines, lyricsType, hasTimestamps, err := service.parseLyrics(lyricsData)
+			parsed, err := service.parseLyrics(context.Background(), lyricsData)
 
 			assert.NoError(t, err)
-			assert.Equal(t, tt.expectedType, lyricsType)
-			assert.Equal(t, tt.expectedTimestamp, hasTimestamps)
 
 			if tt.shouldBeNil {
-				assert.Nil(t, lines)
+				assert.Nil(t, parsed)
 			} else {
-				assert.NotNil(t, lines)
-				assert.Len(t, lines, tt.expectedLines)
+				assert.NotNil(t, parsed)
+				assert.Equal(t, tt.expectedType, parsed.lyricsType)
+				assert.Equal(t, tt.expectedTimestamp, parsed.hasTimestamps)
+				assert.Len(t, parsed.lines, tt.expectedLines)
 
 				if tt.expectedFirstLine != "" {
-					assert.Equal(t, tt.expectedFirstLine, lines[0].Text)
+					assert.Equal(t, tt.expectedFirstLine, parsed.lines[0].Text)
 				}
 			}
 		})
@@ -110,8 +122,9 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		ctx := context.Background()
 		lyricsData := &model.LyricsSourceData{
@@ -127,7 +140,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 
 		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
 
-		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -136,17 +149,86 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		assert.Equal(t, model.LyricsTypeSynced, response.Lyrics.Type)
 		assert.True(t, response.Lyrics.HasTimestamps)
 		assert.Equal(t, 2, response.Lyrics.TotalLines)
+		assert.Equal(t, TokenizerUnicode, response.Lyrics.Tokenizer)
+		assert.NotNil(t, response.Statistics)
+		assert.Equal(t, 2, response.Statistics.TotalLines)
+		// Last synced line ends with the track
+		assert.Equal(t, 180000, *response.Lyrics.Lines[1].EndMs)
+		assert.Equal(t, 1, response.Statistics.UniqueLines)
 		assert.Equal(t, model.SourceLRCLib, response.Metadata.Source)
 		assert.GreaterOrEqual(t, response.Metadata.ProcessingTimeMs, int64(0))
 		mockClient.AssertExpectations(t)
 	})
 
+	t.Run("lrc header, length mismatch and trailing break", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		parser := NewParser()
+		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
+
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
+
+		ctx := context.Background()
+		lyricsData := &model.LyricsSourceData{
+			TrackID:      123,
+			TrackName:    "Test Song",
+			ArtistName:   "Test Artist",
+			Duration:     180,
+			SyncedLyrics: "[ar:Test Artist]\n[length:04:00]\n[00:10.00] Test line\n[00:30.00]",
+		}
+
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response.Lyrics.Header)
+		assert.Equal(t, "Test Artist", response.Lyrics.Header.Artist)
+		assert.Equal(t, 2, response.Lyrics.TotalLines)
+		assert.Equal(t, 1, response.Statistics.TotalLines)
+		assert.Len(t, response.Structure.Breaks, 1)
+		assert.Equal(t, 180000, *response.Structure.Breaks[0].EndMs)
+		assert.Equal(t, []string{"LRC length tag (04:00) does not match track duration (180s)"}, response.Metadata.Warnings)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("parser diagnostics", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		parser := NewParser()
+		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
+
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
+
+		ctx := context.Background()
+		lyricsData := &model.LyricsSourceData{
+			TrackName:    "Test Song",
+			ArtistName:   "Test Artist",
+			SyncedLyrics: "[00:10.00] Test line\nNo timestamp here",
+		}
+
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`source line 2: missing timestamp: "No timestamp here"`}, response.Metadata.Warnings)
+		assert.Nil(t, response.Metadata.Diagnostics)
+
+		response, err = service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Debug: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Diagnostic{
+			{LineNumber: 2, Raw: "No timestamp here", Reason: model.DiagnosticMissingTimestamp},
+		}, response.Metadata.Diagnostics)
+		mockClient.AssertExpectations(t)
+	})
+
 	t.Run("instrumental track", func(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		ctx := context.Background()
 		lyricsData := &model.LyricsSourceData{
@@ -162,7 +244,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 
 		mockClient.On("GetLyrics", ctx, "Instrumental Track", "Test Artist").Return(lyricsData, nil)
 
-		response, err := service.AnalyzeSong(ctx, "Instrumental Track", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Instrumental Track", "Test Artist", AnalyzeOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -177,8 +259,9 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		ctx := context.Background()
 		lyricsData := &model.LyricsSourceData{
@@ -194,7 +277,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 
 		mockClient.On("GetLyrics", ctx, "No Lyrics Track", "Test Artist").Return(lyricsData, nil)
 
-		response, err := service.AnalyzeSong(ctx, "No Lyrics Track", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "No Lyrics Track", "Test Artist", AnalyzeOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -207,15 +290,16 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		ctx := context.Background()
 		expectedError := errors.New("client error")
 
 		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(nil, expectedError)
 
-		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -227,8 +311,9 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		ctx := context.Background()
 		lyricsData := &model.LyricsSourceData{
@@ -244,7 +329,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 
 		mockClient.On("GetLyrics", ctx, "Song with Chorus", "Test Artist").Return(lyricsData, nil)
 
-		response, err := service.AnalyzeSong(ctx, "Song with Chorus", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Song with Chorus", "Test Artist", AnalyzeOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -256,12 +341,42 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 
+	t.Run("plain lyrics with section headers", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		parser := NewParser()
+		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
+
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
+
+		ctx := context.Background()
+		lyricsData := &model.LyricsSourceData{
+			TrackName:   "Song with Sections",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "[Verse 1]\nVerse line\n[Chorus]\nChorus line\n[Verse 2]\nOther verse\n[Chorus]\nChorus line",
+		}
+
+		mockClient.On("GetLyrics", ctx, "Song with Sections", "Test Artist").Return(lyricsData, nil)
+
+		response, err := service.AnalyzeSong(ctx, "Song with Sections", "Test Artist", AnalyzeOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, response.Lyrics.TotalLines)
+		assert.Equal(t, 8, response.Statistics.TotalWords)
+		assert.Len(t, response.Structure.Sections, 4)
+		assert.Equal(t, model.SectionChorus, response.Structure.Sections[1].Type)
+		assert.Equal(t, "V-C-V-C", response.Structure.Form)
+		assert.Equal(t, "Chorus line", response.Structure.Chorus.Text)
+		assert.Equal(t, 2, response.Structure.Chorus.Occurrences)
+		mockClient.AssertExpectations(t)
+	})
+
 	t.Run("nil chorus detector - graceful degradation", func(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 
-		// Create service without chorus detector (nil)
-		service := NewLyricsService(mockClient, parser, nil)
+		// Create service without chorus detector (nil) or statistics
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(nil))
 
 		ctx := context.Background()
 		lyricsData := &model.LyricsSourceData{
@@ -277,7 +392,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 
 		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
 
-		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
 
 		// Should succeed even without chorus detector (graceful degradation)
 		assert.NoError(t, err)
@@ -287,6 +402,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		assert.NotNil(t, response.Structure)
 		assert.NotNil(t, response.Structure.Chorus)
 		assert.False(t, response.Structure.Chorus.Detected)
+		assert.Nil(t, response.Statistics)
 		mockClient.AssertExpectations(t)
 	})
 
@@ -294,8 +410,9 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient := new(MockLyricsClient)
 		parser := NewParser()
 		chorusDetector := NewChorusDetector()
+		statsCalc := NewStatisticsCalculator()
 
-		service := NewLyricsService(mockClient, parser, chorusDetector)
+		service := NewLyricsService(mockClient, parser, NewStructureAnalyzer(chorusDetector), NewStatisticsAnalyzer(statsCalc))
 
 		// Create a cancelled context
 		ctx, cancel := context.WithCancel(context.Background())
@@ -304,7 +421,7 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		// Mock client should return context.Canceled error
 		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(nil, context.Canceled)
 
-		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist")
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -312,3 +429,510 @@ func TestLyricsService_AnalyzeSong(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+// recordingAnalyzer is a custom analyzer that records the order it ran in
+type recordingAnalyzer struct {
+	name  string
+	order *[]string
+	input **AnalysisInput
+	err   error
+}
+
+func (a *recordingAnalyzer) Name() string {
+	return a.name
+}
+
+func (a *recordingAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	*a.order = append(*a.order, a.name)
+	if a.input != nil {
+		*a.input = input
+	}
+	if a.err != nil {
+		return a.err
+	}
+	response.Extensions[a.name] = len(input.Lines)
+	return nil
+}
+
+func TestLyricsService_Analyzers(t *testing.T) {
+	lyricsData := &model.LyricsSourceData{
+		TrackName:   "Test Song",
+		ArtistName:  "Test Artist",
+		PlainLyrics: "Line one\nLine two",
+	}
+
+	t.Run("custom analyzers run in order and add named sections", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		var order []string
+		service := NewLyricsService(mockClient, NewParser(),
+			&recordingAnalyzer{name: "first", order: &order},
+			NewStatisticsAnalyzer(NewStatisticsCalculator()),
+			&recordingAnalyzer{name: "second", order: &order},
+		)
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, order)
+		assert.Equal(t, 2, response.Extensions["first"])
+		assert.Equal(t, 2, response.Extensions["second"])
+		assert.NotNil(t, response.Statistics)
+		assert.Nil(t, response.Structure)
+	})
+
+	t.Run("analyzers annotate the returned lines", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:   "Test Song",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "Dancing through the night\nHolding on so tight",
+		}, nil)
+
+		service := NewLyricsService(mockClient, NewParser(), NewStructureAnalyzer(NewChorusDetector()), NewRhymeAnalyzer())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "A", response.Lyrics.Lines[1].RhymeGroup)
+		assert.Equal(t, "AA", response.Rhyme.Schemes[0].Scheme)
+		assert.Equal(t, "Verse 1", response.Rhyme.Schemes[0].Label)
+	})
+
+	t.Run("include filters sections without changing per-section results", func(t *testing.T) {
+		source := &model.LyricsSourceData{
+			TrackName:   "Test Song",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "Walking in the rain\nCalling out your name\nHold me close tonight\nEverything's alright\nLosing all the pain\nNothing is the same\nHold me close tonight\nEverything's alright",
+		}
+		analyze := func(opts AnalyzeOptions) *model.SongAnalysisResponse {
+			mockClient := new(MockLyricsClient)
+			ctx := context.Background()
+			mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(source, nil)
+
+			service := NewLyricsService(mockClient, NewParser(), NewStructureAnalyzer(NewChorusDetector()), NewRhymeAnalyzer(), NewSentimentAnalyzer())
+			response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", opts)
+			require.NoError(t, err)
+			return response
+		}
+
+		full := analyze(AnalyzeOptions{})
+		filtered := analyze(AnalyzeOptions{Include: []string{AnalyzerRhyme, AnalyzerSentiment}})
+
+		require.NotNil(t, full.Structure)
+		assert.Len(t, full.Rhyme.Schemes, 4)
+		assert.Nil(t, filtered.Structure)
+		assert.Equal(t, full.Rhyme, filtered.Rhyme)
+		assert.Equal(t, full.Sentiment.Sections, filtered.Sentiment.Sections)
+	})
+
+	t.Run("failing analyzer degrades to a warning", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		var order []string
+		service := NewLyricsService(mockClient, NewParser(),
+			&recordingAnalyzer{name: "broken", order: &order, err: errors.New("boom")},
+			NewStructureAnalyzer(NewChorusDetector()),
+		)
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response.Structure)
+		assert.Nil(t, response.Extensions)
+		assert.Contains(t, response.Metadata.Warnings, "broken analysis failed: boom")
+	})
+}
+
+func TestLyricsService_IncludeExclude(t *testing.T) {
+	lyricsData := &model.LyricsSourceData{
+		TrackName:   "Test Song",
+		ArtistName:  "Test Artist",
+		PlainLyrics: "Line one\nLine two\nLine one",
+	}
+
+	tests := []struct {
+		name           string
+		opts           AnalyzeOptions
+		wantLyrics     bool
+		wantLines      bool
+		wantStructure  bool
+		wantStatistics bool
+		ran            []string
+	}{
+		{
+			name:           "default returns everything",
+			opts:           AnalyzeOptions{},
+			wantLyrics:     true,
+			wantLines:      true,
+			wantStructure:  true,
+			wantStatistics: true,
+			ran:            []string{"custom"},
+		},
+		{
+			name:           "include structure and statistics only",
+			opts:           AnalyzeOptions{Include: []string{"structure", "statistics"}},
+			wantStructure:  true,
+			wantStatistics: true,
+		},
+		{
+			name:           "exclude lines keeps the lyrics summary",
+			opts:           AnalyzeOptions{Exclude: []string{"lines"}},
+			wantLyrics:     true,
+			wantStructure:  true,
+			wantStatistics: true,
+			ran:            []string{"custom"},
+		},
+		{
+			name:       "include lines implies lyrics",
+			opts:       AnalyzeOptions{Include: []string{"lines"}},
+			wantLyrics: true,
+			wantLines:  true,
+		},
+		{
+			name:           "exclude skips analyzers",
+			opts:           AnalyzeOptions{Exclude: []string{"structure", "custom"}},
+			wantLyrics:     true,
+			wantLines:      true,
+			wantStatistics: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockLyricsClient)
+			ctx := context.Background()
+			mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+			var ran []string
+			service := NewLyricsService(mockClient, NewParser(),
+				NewStructureAnalyzer(NewChorusDetector()),
+				NewStatisticsAnalyzer(NewStatisticsCalculator()),
+				&recordingAnalyzer{name: "custom", order: &ran},
+			)
+
+			response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", tt.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLyrics, response.Lyrics != nil)
+			assert.Equal(t, tt.wantLines, response.Lyrics != nil && response.Lyrics.Lines != nil)
+			assert.Equal(t, tt.wantStructure, response.Structure != nil)
+			assert.Equal(t, tt.wantStatistics, response.Statistics != nil)
+			assert.Equal(t, tt.ran, ran)
+		})
+	}
+
+	t.Run("unknown section is rejected before fetching", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		service := NewLyricsService(mockClient, NewParser(), NewStatisticsAnalyzer(NewStatisticsCalculator()))
+
+		response, err := service.AnalyzeSong(context.Background(), "Test Song", "Test Artist", AnalyzeOptions{Include: []string{"structure"}})
+
+		assert.ErrorIs(t, err, ErrUnknownSection)
+		assert.Nil(t, response)
+		mockClient.AssertNotCalled(t, "GetLyrics")
+	})
+}
+
+func TestLyricsService_SimilarityThreshold(t *testing.T) {
+	lyricsData := &model.LyricsSourceData{
+		TrackName:   "Test Song",
+		ArtistName:  "Test Artist",
+		PlainLyrics: "Hold me closer now\nFirst verse\nHold me closer, now!\nSecond verse\nHold me closer now girl",
+	}
+
+	tests := []struct {
+		name        string
+		threshold   float64
+		occurrences int
+	}{
+		{name: "default threshold", threshold: 0, occurrences: 2},
+		{name: "looser threshold from the request", threshold: 0.7, occurrences: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockLyricsClient)
+			ctx := context.Background()
+			mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+			service := NewLyricsService(mockClient, NewParser(), NewStructureAnalyzer(NewChorusDetector()))
+
+			response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{SimilarityThreshold: tt.threshold})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.occurrences, response.Structure.Chorus.Occurrences)
+		})
+	}
+
+	t.Run("invalid threshold is rejected", func(t *testing.T) {
+		service := NewLyricsService(new(MockLyricsClient), NewParser(), NewStructureAnalyzer(NewChorusDetector()))
+
+		_, err := service.AnalyzeSong(context.Background(), "Test Song", "Test Artist", AnalyzeOptions{SimilarityThreshold: 2})
+
+		assert.ErrorIs(t, err, ErrInvalidThreshold)
+	})
+}
+
+func TestLyricsService_Language(t *testing.T) {
+	lyricsData := &model.LyricsSourceData{
+		TrackName:   "Test Song",
+		ArtistName:  "Test Artist",
+		PlainLyrics: "Te quiero mucho mi vida\nBailando bajo la luna\nOh oh\nBaby I love you so much",
+	}
+
+	t.Run("detects the main language and tags lines", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		var ran []string
+		var input *AnalysisInput
+		service := NewLyricsService(mockClient, NewParser(), &recordingAnalyzer{name: "custom", order: &ran, input: &input})
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		require.NoError(t, err)
+		require.NotNil(t, response.Lyrics.Language)
+		assert.Equal(t, "es", response.Lyrics.Language.Code)
+		assert.Contains(t, response.Lyrics.Language.Shares, "en")
+		assert.Equal(t, "es", input.Language)
+
+		var tags []string
+		for _, line := range response.Lyrics.Lines {
+			tags = append(tags, line.Language)
+		}
+		assert.Equal(t, []string{"es", "es", "", "en"}, tags)
+	})
+
+	t.Run("detection is skipped when nothing uses it", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		var ran []string
+		var input *AnalysisInput
+		service := NewLyricsService(mockClient, NewParser(), &recordingAnalyzer{name: AnalyzerStructure, order: &ran, input: &input})
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Exclude: []string{FieldLyrics}})
+
+		require.NoError(t, err)
+		assert.Nil(t, response.Lyrics)
+		assert.Empty(t, input.Language)
+		for _, line := range input.Lines {
+			assert.Empty(t, line.Language)
+		}
+	})
+
+	t.Run("language filter limits the returned lines", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		service := NewLyricsService(mockClient, NewParser())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Languages: []string{"en"}})
+
+		require.NoError(t, err)
+		require.Len(t, response.Lyrics.Lines, 1)
+		assert.Equal(t, "Baby I love you so much", response.Lyrics.Lines[0].Text)
+		assert.Equal(t, 4, response.Lyrics.TotalLines)
+	})
+
+	t.Run("unknown language is rejected before fetching", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		service := NewLyricsService(mockClient, NewParser())
+
+		response, err := service.AnalyzeSong(context.Background(), "Test Song", "Test Artist", AnalyzeOptions{Languages: []string{"xx"}})
+
+		assert.ErrorIs(t, err, ErrUnknownLanguage)
+		assert.Nil(t, response)
+		mockClient.AssertNotCalled(t, "GetLyrics")
+	})
+}
+
+func TestLyricsService_Clean(t *testing.T) {
+	lyricsData := &model.LyricsSourceData{
+		TrackName:    "Test Song",
+		ArtistName:   "Test Artist",
+		Duration:     20,
+		SyncedLyrics: "[00:01.00] Oh shit, here we go\n[00:05.00] Walking down the street",
+	}
+
+	t.Run("masks explicit words and keeps timestamps", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		service := NewLyricsService(mockClient, NewParser(), NewProfanityAnalyzer())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Clean: true})
+
+		require.NoError(t, err)
+		lines := response.Lyrics.Lines
+		require.Len(t, lines, 2)
+		assert.Equal(t, "Oh ****, here we go", lines[0].Text)
+		require.NotNil(t, lines[0].StartMs)
+		require.NotNil(t, lines[0].EndMs)
+		assert.Equal(t, 1000, *lines[0].StartMs)
+		assert.Equal(t, 5000, *lines[0].EndMs)
+		assert.True(t, lines[0].Explicit)
+		assert.Equal(t, "Walking down the street", lines[1].Text)
+		assert.Equal(t, "****", response.Profanity.Lines[0].Words[0].Text)
+	})
+
+	t.Run("masks even when the profanity section is excluded", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(lyricsData, nil)
+
+		service := NewLyricsService(mockClient, NewParser(), NewProfanityAnalyzer())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Clean: true, Exclude: []string{AnalyzerProfanity}})
+
+		require.NoError(t, err)
+		assert.Nil(t, response.Profanity)
+		assert.Equal(t, "Oh ****, here we go", response.Lyrics.Lines[0].Text)
+	})
+
+	t.Run("masks warnings and diagnostics", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:    "Test Song",
+			ArtistName:   "Test Artist",
+			Duration:     20,
+			SyncedLyrics: "[00:01.00] Walking down the street\nshit without a timestamp\n[00:05.00] Lyrics by Shitty Records",
+		}, nil)
+
+		service := NewLyricsService(mockClient, NewParser(), NewProfanityAnalyzer())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{Clean: true, Debug: true})
+
+		require.NoError(t, err)
+		require.Len(t, response.Metadata.Warnings, 2)
+		for _, warning := range response.Metadata.Warnings {
+			assert.NotContains(t, strings.ToLower(warning), "shit")
+		}
+		require.Len(t, response.Metadata.Diagnostics, 1)
+		assert.Equal(t, "**** without a timestamp", response.Metadata.Diagnostics[0].Raw)
+	})
+
+	t.Run("rejected without a text masker", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		service := NewLyricsService(mockClient, NewParser())
+
+		response, err := service.AnalyzeSong(context.Background(), "Test Song", "Test Artist", AnalyzeOptions{Clean: true})
+
+		assert.ErrorIs(t, err, ErrCleanUnavailable)
+		assert.Nil(t, response)
+		mockClient.AssertNotCalled(t, "GetLyrics")
+	})
+}
+
+func TestLyricsService_JunkLines(t *testing.T) {
+	t.Run("removes junk lines and warns", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:   "Test Song",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "Lyrics by John Smith\nHold me closer now\nFirst verse\nhttps://lyrics.example.com",
+		}, nil)
+
+		service := NewLyricsService(mockClient, NewParser())
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Lyrics.TotalLines)
+		assert.Equal(t, "Hold me closer now", response.Lyrics.Lines[0].Text)
+		assert.Equal(t, 1, response.Lyrics.Lines[0].LineNumber)
+		assert.Equal(t, []string{
+			`lyric line 1: credit line removed: "Lyrics by John Smith"`,
+			`lyric line 4: url line removed: "https://lyrics.example.com"`,
+		}, response.Metadata.Warnings)
+	})
+
+	t.Run("placeholder-only lyrics are instrumental", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:    "Test Song",
+			ArtistName:   "Test Artist",
+			SyncedLyrics: "[00:01.00] ♪\n[00:30.00] ♪ ♪",
+		}, nil)
+
+		service := NewLyricsService(mockClient, NewParser(), NewStatisticsAnalyzer(NewStatisticsCalculator()))
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, response.Track.Instrumental)
+		assert.Nil(t, response.Lyrics)
+		assert.Nil(t, response.Statistics)
+		assert.Equal(t, "Instrumental track - no lyrics available", response.Metadata.Message)
+		assert.Len(t, response.Metadata.Warnings, 2)
+	})
+
+	t.Run("instrumental header in plain lyrics is instrumental", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:   "Test Song",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "[Instrumental]",
+		}, nil)
+
+		response, err := NewLyricsService(mockClient, NewParser()).AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, response.Track.Instrumental)
+	})
+
+	for name, source := range map[string]*model.LyricsSourceData{
+		"header-only synced lyrics are empty, not instrumental": {SyncedLyrics: "[ar:Test Artist]\n[ti:Test Song]"},
+		"junk-only lyrics are empty, not instrumental":          {PlainLyrics: "Lyrics by John Smith\nwww.lyricsite.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockClient := new(MockLyricsClient)
+			ctx := context.Background()
+			source.TrackName, source.ArtistName = "Test Song", "Test Artist"
+			mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(source, nil)
+
+			service := NewLyricsService(mockClient, NewParser(), NewStatisticsAnalyzer(NewStatisticsCalculator()))
+
+			response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+			require.NoError(t, err)
+			assert.False(t, response.Track.Instrumental)
+			assert.Nil(t, response.Lyrics)
+			assert.Nil(t, response.Statistics)
+			assert.Equal(t, "No lyrics available for this track", response.Metadata.Message)
+			assert.Contains(t, response.Metadata.Warnings, "no lyric lines left after parsing and cleaning")
+		})
+	}
+
+	t.Run("nil cleaner keeps every line", func(t *testing.T) {
+		mockClient := new(MockLyricsClient)
+		ctx := context.Background()
+		mockClient.On("GetLyrics", ctx, "Test Song", "Test Artist").Return(&model.LyricsSourceData{
+			TrackName:   "Test Song",
+			ArtistName:  "Test Artist",
+			PlainLyrics: "Lyrics by John Smith\nHold me closer now",
+		}, nil)
+
+		service := NewLyricsServiceWithCleaner(mockClient, NewParser(), nil)
+
+		response, err := service.AnalyzeSong(ctx, "Test Song", "Test Artist", AnalyzeOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Lyrics.TotalLines)
+		assert.Empty(t, response.Metadata.Warnings)
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// Response parts that can be selected alongside the analyzer sections
+const (
+	FieldLyrics = "lyrics"
+	FieldLines  = "lines"
+)
+
+// ErrUnknownSection is returned when include or exclude names a section
+// that neither the response nor any configured analyzer provides
+var ErrUnknownSection = errors.New("unknown analysis section")
+
+// AnalyzeOptions controls optional parts of the analysis response
+type AnalyzeOptions struct {
+	// Debug adds structured parser diagnostics to the response metadata
+	Debug bool
+
+	// Include limits the response to these sections; empty means all of them
+	Include []string
+
+	// Exclude leaves these sections out; "lines" drops only the lyric lines,
+	// which are then returned as null
+	Exclude []string
+
+	// SimilarityThreshold overrides how alike lines must be to count as a
+	// repeated chorus line, from 0 to 1; zero keeps the detector's default
+	SimilarityThreshold float64
+
+	// Languages limits the returned lines to those tagged with one of these
+	// ISO 639-1 codes; empty returns every line
+	Languages []string
+
+	// Clean masks explicit words in the returned lyrics, keeping timestamps
+	Clean bool
+}
+
+// ErrInvalidThreshold is returned for a similarity threshold outside 0 to 1
+var ErrInvalidThreshold = errors.New("similarity threshold must be between 0 and 1")
+
+// ErrUnknownLanguage is returned when the language filter names a language
+// the detector cannot report
+var ErrUnknownLanguage = errors.New("unknown language")
+
+// ErrCleanUnavailable is returned when a clean edit is requested but no
+// configured analyzer can mask explicit words
+var ErrCleanUnavailable = errors.New("clean edit requires a text masker")
+
+// validate checks that every included or excluded name is a known section,
+// that the similarity threshold is in range, that every filtered language
+// can be detected and that a clean edit can be made
+func (o AnalyzeOptions) validate(analyzers []Analyzer, detector *LanguageDetector) error {
+	if o.SimilarityThreshold < 0 || o.SimilarityThreshold > 1 {
+		return ErrInvalidThreshold
+	}
+
+	known := []string{FieldLyrics, FieldLines}
+	for _, analyzer := range analyzers {
+		known = append(known, analyzer.Name())
+	}
+
+	for _, name := range slices.Concat(o.Include, o.Exclude) {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("%w: %s", ErrUnknownSection, name)
+		}
+	}
+
+	for _, code := range o.Languages {
+		if !detector.knownLanguage(code) {
+			return fmt.Errorf("%w: %s", ErrUnknownLanguage, code)
+		}
+	}
+
+	if o.Clean && findTextMasker(analyzers) == nil {
+		return ErrCleanUnavailable
+	}
+
+	return nil
+}
+
+// wants reports whether the named section should be computed and returned
+func (o AnalyzeOptions) wants(name string) bool {
+	if len(o.Include) > 0 && !slices.Contains(o.Include, name) {
+		return false
+	}
+	return !slices.Contains(o.Exclude, name)
+}
+
+// wantsLyrics reports whether the lyrics section is returned. Including
+// "lines" implies the lyrics section that holds them.
+func (o AnalyzeOptions) wantsLyrics() bool {
+	return o.wants(FieldLyrics) || (slices.Contains(o.Include, FieldLines) && !slices.Contains(o.Exclude, FieldLyrics))
+}
+
+// wantsLines reports whether the lyric lines are returned
+func (o AnalyzeOptions) wantsLines() bool {
+	return o.wantsLyrics() && !slices.Contains(o.Exclude, FieldLines)
+}
+
+// sectionAnalyzers report per section, reading the sections the structure
+// analyzer finds
+var sectionAnalyzers = []string{AnalyzerRhyme, AnalyzerFlow, AnalyzerSentiment}
+
+// computes reports whether the named analyzer has to run: when its section
+// is wanted, or for structure, when a wanted analyzer reports per section,
+// so that include and exclude only filter the output
+func (o AnalyzeOptions) computes(name string) bool {
+	if o.wants(name) {
+		return true
+	}
+	if name != AnalyzerStructure {
+		return false
+	}
+	for _, dependent := range sectionAnalyzers {
+		if o.wants(dependent) {
+			return true
+		}
+	}
+	return false
+}
+
+// languageFreeAnalyzers are the built-in analyzers that read neither the
+// song's language nor its lines' languages
+var languageFreeAnalyzers = []string{AnalyzerStructure}
+
+// needsLanguage reports whether language detection has to run: for the
+// lyrics section, for masking, or for a wanted analyzer that may use it
+func (o AnalyzeOptions) needsLanguage(analyzers []Analyzer) bool {
+	if o.wantsLyrics() || o.Clean {
+		return true
+	}
+	for _, analyzer := range analyzers {
+		if o.wants(analyzer.Name()) && !slices.Contains(languageFreeAnalyzers, analyzer.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterLanguages returns the lines tagged with one of the requested
+// languages, or all lines when no language filter is set
+func (o AnalyzeOptions) filterLanguages(lines []model.LyricLine) []model.LyricLine {
+	if len(o.Languages) == 0 {
+		return lines
+	}
+
+	filtered := make([]model.LyricLine, 0, len(lines))
+	for _, line := range lines {
+		if slices.Contains(o.Languages, line.Language) {
+			filtered = append(filtered, line)
+		}
+	}
+	return filtered
+}
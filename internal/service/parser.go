@@ -2,16 +2,91 @@ package service
 
 import "regexp"
 
+// Parser implements LyricsParser
+var _ LyricsParser = (*Parser)(nil)
+
 // Parser handles lyrics parsing and implements the LyricsParser interface
 type Parser struct {
-	timestampRegex *regexp.Regexp
+	timestampRegex     *regexp.Regexp
+	timeTagRegex       *regexp.Regexp
+	wordTimestampRegex *regexp.Regexp
+	headerRegex        *regexp.Regexp
+	idTagRegex         *regexp.Regexp
+	srtTimingRegex     *regexp.Regexp
+	cueTimingRegex     *regexp.Regexp
+	cueMarkupRegex     *regexp.Regexp
+	sectionHeaderRegex *regexp.Regexp
+	singerRegex        *regexp.Regexp
+	vttVoiceRegex      *regexp.Regexp
+	tokenizer          Tokenizer
+	limits             ParseLimits
+}
+
+// ParserConfig configures a parser
+type ParserConfig struct {
+	// Tokenizer counts words; defaults to the script-aware tokenizer
+	Tokenizer Tokenizer
+
+	// Limits bounds the accepted input size
+	Limits ParseLimits
 }
 
-// NewParser creates a new parser instance
+// NewParser creates a new parser instance using the script-aware tokenizer
 func NewParser() *Parser {
+	return NewParserWithTokenizer(NewUnicodeTokenizer())
+}
+
+// NewParserWithTokenizer creates a new parser that counts words with the given tokenizer
+func NewParserWithTokenizer(tokenizer Tokenizer) *Parser {
+	return NewParserWithConfig(ParserConfig{
+		Tokenizer: tokenizer,
+		Limits:    DefaultParseLimits(),
+	})
+}
+
+// NewParserWithConfig creates a new parser with the given tokenizer and input limits
+func NewParserWithConfig(cfg ParserConfig) *Parser {
+	tokenizer := cfg.Tokenizer
+	if tokenizer == nil {
+		tokenizer = NewUnicodeTokenizer()
+	}
+
 	return &Parser{
+		tokenizer: tokenizer,
+		limits:    cfg.Limits,
 		// Matches: [mm:ss.xx] or [mm:ss.xxx] text
-		// Supports both 2-digit (00:10.50) and 3-digit (00:10.500) milliseconds
-		timestampRegex: regexp.MustCompile(`\[(\d+):(\d+\.\d{2,3})\]\s*(.+)`),
+		// Supports both 2-digit (00:10.50) and 3-digit (00:10.500) milliseconds,
+		// and compressed lines with several tags: [00:12.00][01:05.30] text
+		timestampRegex: regexp.MustCompile(`((?:\[\d+:\d+\.\d{2,3}\]\s*)+)(.*)`),
+		// Matches a single [mm:ss.xx] tag within the tag group above
+		timeTagRegex: regexp.MustCompile(`\[(\d+):(\d+\.\d{2,3})\]`),
+		// Matches enhanced LRC (A2) inline word tags: <mm:ss.xx> or <mm:ss.xxx>
+		wordTimestampRegex: regexp.MustCompile(`<(\d+):(\d+\.\d{2,3})>`),
+		// Matches LRC ID tags: [ar:Artist], [offset:+250], etc.
+		headerRegex: regexp.MustCompile(`(?i)^\[(ar|ti|al|by|length|offset):(.*)\]$`),
+		// Matches any other ID tag such as [re:], [ve:] or [#:]
+		idTagRegex: regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`),
+		// Matches an SRT timing line: 00:01:02,500 --> (comma before milliseconds)
+		srtTimingRegex: regexp.MustCompile(`(?m)^\d+:\d{2}:\d{2},\d{3}\s*-->`),
+		// Matches SRT or WebVTT cue timings: [hh:]mm:ss.mmm --> [hh:]mm:ss.mmm
+		cueTimingRegex: regexp.MustCompile(`(?m)^((?:\d+:)?\d{1,2}:\d{2}[.,]\d{3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{3})`),
+		// Matches cue markup: HTML-like tags (<i>, <v Singer>) and ASS overrides ({\an8})
+		cueMarkupRegex: regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`),
+		// Matches section headers in plain lyrics: [Chorus], [Verse 2: Artist]
+		sectionHeaderRegex: regexp.MustCompile(`^\[\s*([A-Za-z][A-Za-z -]*?)\s*(\d+)?\s*(?::\s*([^\]]*))?\]$`),
+		// Matches leading speaker markers: v1: text, M: text, (Artist:) text
+		singerRegex: regexp.MustCompile(`^(?:(v\d+|[MFD])\s*:|\(([^():]+):\))\s*(.+)$`),
+		// Matches a WebVTT voice span: <v Singer> or <v.loud Singer>
+		vttVoiceRegex: regexp.MustCompile(`<v(?:\.[^\s>]*)?\s+([^>]+)>`),
 	}
 }
+
+// Tokenizer returns the tokenizer used to count words
+func (p *Parser) Tokenizer() Tokenizer {
+	return p.tokenizer
+}
+
+// countWords counts the words in text using the parser's tokenizer
+func (p *Parser) countWords(text string) int {
+	return len(p.tokenizer.Tokenize(text))
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
@@ -9,30 +11,85 @@ import (
 
 // ParsePlainLyrics parses plain lyrics without timestamps
 func (p *Parser) ParsePlainLyrics(plainLyrics string) ([]model.LyricLine, error) {
-	if plainLyrics == "" {
-		return nil, fmt.Errorf("plain lyrics are empty")
-	}
+	lines, _, err := p.ParsePlainLyricsWithSections(plainLyrics)
+	return lines, err
+}
 
-	lines := strings.Split(plainLyrics, "\n")
+// ParsePlainLyricsWithSections parses plain lyrics and turns section headers
+// such as [Chorus] or [Verse 2: Artist] into labelled sections instead of lines
+func (p *Parser) ParsePlainLyricsWithSections(plainLyrics string) ([]model.LyricLine, []model.Section, error) {
+	return p.ParsePlainLyricsReader(context.Background(), strings.NewReader(plainLyrics))
+}
+
+// ParsePlainLyricsReader parses plain lyrics and their section headers from r
+func (p *Parser) ParsePlainLyricsReader(ctx context.Context, r io.Reader) ([]model.LyricLine, []model.Section, error) {
 	var lyricLines []model.LyricLine
+
+	sections, err := p.StreamPlainLyrics(ctx, r, func(line model.LyricLine) error {
+		lyricLines = append(lyricLines, line)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lyricLines, sections, nil
+}
+
+// StreamPlainLyrics parses plain lyrics from r and passes each line to yield
+// as soon as it is read. Sections are returned once the input is exhausted,
+// since a section's end is only known when the next one starts. Parsing
+// stops at the first error returned by yield.
+func (p *Parser) StreamPlainLyrics(ctx context.Context, r io.Reader, yield func(model.LyricLine) error) ([]model.Section, error) {
+	scanner := p.newLineScanner(ctx, r)
+	var sections []model.Section
+	var openSection *model.Section
 	lineNumber := 1
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
 			continue
 		}
 
-		wordCount := len(strings.Fields(line))
+		// Section headers start a new section and are not lyric lines
+		if section := p.parseSectionHeader(line); section != nil {
+			sections = closeSection(sections, openSection, lineNumber-1)
+			section.StartLine = lineNumber
+			openSection = section
+			continue
+		}
+
+		// Lines without their own marker are sung by the section's performer
+		singer, text := p.extractSinger(line)
+		if singer == "" && openSection != nil {
+			singer = openSection.Performer
+		}
+
+		wordCount := p.countWords(text)
 
-		lyricLines = append(lyricLines, model.LyricLine{
+		err := yield(model.LyricLine{
 			LineNumber: lineNumber,
-			Text:       line,
+			Text:       text,
+			Singer:     singer,
 			WordCount:  wordCount,
 		})
+		if err != nil {
+			return nil, err
+		}
 
 		lineNumber++
 	}
 
-	return lyricLines, nil
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if scanner.BytesRead() == 0 {
+		return nil, fmt.Errorf("plain lyrics are empty")
+	}
+
+	sections = closeSection(sections, openSection, lineNumber-1)
+
+	return sections, nil
 }
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// minVisibleLetters is the fewest unmasked letters a self-censored word such
+// as "f*ck" needs to be matched, so a fully masked "****" is not flagged
+const minVisibleLetters = 2
+
+// maskRunes stand for hidden letters in self-censored words
+const maskRunes = "*#"
+
+// bundledProfanity holds the default explicit word lists, one file per ISO 639-1 code
+//
+//go:embed data/profanity/*.txt
+var bundledProfanity embed.FS
+
+// leetLetters maps characters commonly swapped for letters back to them
+var leetLetters = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't',
+	'@': 'a', '$': 's', '!': 'i',
+}
+
+// foldAccents strips accents so "cabrón" and "cabron" match the same entry
+var foldAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c", "ß", "ss",
+)
+
+// profanityEntry is one word list entry; a prefix entry also matches any
+// ending, so "fuck*" covers "fucking"
+type profanityEntry struct {
+	word      []rune
+	collapsed []rune // word with repeated letters collapsed, for stretched spellings
+	prefix    bool
+	text      string
+}
+
+// wordSpan is a whitespace-separated word and its byte offsets in a line
+type wordSpan struct {
+	text       string
+	start, end int
+	field      int // index among all whitespace-separated fields, including bare punctuation
+}
+
+// profaneWord is an explicit word found in a line
+type profaneWord struct {
+	position int
+	span     wordSpan
+	match    string
+}
+
+// ProfanityAnalyzer flags explicit words using per-language word lists. It
+// sees through leetspeak ("sh1t"), self-censoring ("f*ck"), stretched
+// letters ("fuuuck"), accents and inflections. Lines tagged with a language
+// that has a list are checked against that list, other lines against all.
+type ProfanityAnalyzer struct {
+	lists map[string][]profanityEntry
+	all   []profanityEntry
+}
+
+// NewProfanityAnalyzer creates a profanity analyzer with the bundled word lists
+func NewProfanityAnalyzer() *ProfanityAnalyzer {
+	return NewProfanityAnalyzerWithLists(nil)
+}
+
+// NewProfanityAnalyzerWithLists creates a profanity analyzer with the bundled
+// word lists extended by extra words per language code
+func NewProfanityAnalyzerWithLists(extra map[string][]string) *ProfanityAnalyzer {
+	words := make(map[string][]string)
+	for code, text := range readLanguageFiles(bundledProfanity, "data/profanity") {
+		words[code] = parseWordList(text)
+	}
+	for code, list := range extra {
+		words[code] = append(words[code], list...)
+	}
+
+	a := &ProfanityAnalyzer{lists: make(map[string][]profanityEntry)}
+	for _, code := range slices.Sorted(maps.Keys(words)) {
+		for _, word := range words[code] {
+			entry := newProfanityEntry(word)
+			if len(entry.word) == 0 {
+				continue
+			}
+			a.lists[code] = append(a.lists[code], entry)
+			a.all = append(a.all, entry)
+		}
+	}
+
+	return a
+}
+
+// LoadWordLists reads word lists from dir, one "<code>.txt" file per
+// language with one word per line. An empty dir loads nothing.
+func LoadWordLists(dir string) (map[string][]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word lists: %w", err)
+	}
+
+	lists := make(map[string][]string)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".txt" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read word list %s: %w", entry.Name(), err)
+		}
+		lists[strings.TrimSuffix(entry.Name(), ".txt")] = parseWordList(string(data))
+	}
+
+	return lists, nil
+}
+
+// parseWordList reads one lowercase word per line, skipping blank lines and # comments
+func parseWordList(text string) []string {
+	var words []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, strings.ToLower(line))
+	}
+	return words
+}
+
+// newProfanityEntry prepares a list word for matching
+func newProfanityEntry(word string) profanityEntry {
+	prefix := strings.HasSuffix(word, "*")
+	folded := []rune(foldAccents.Replace(strings.ToLower(strings.TrimSuffix(word, "*"))))
+	return profanityEntry{
+		word:      folded,
+		collapsed: collapseRepeats(folded),
+		prefix:    prefix,
+		text:      word,
+	}
+}
+
+// Name implements Analyzer
+func (a *ProfanityAnalyzer) Name() string {
+	return AnalyzerProfanity
+}
+
+// Analyze implements Analyzer. Lines with explicit words are marked explicit.
+func (a *ProfanityAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	profanity := &model.Profanity{}
+	lyricLines := 0
+
+	for i, line := range input.Lines {
+		if line.IsBreak || line.Text == "" {
+			continue
+		}
+		lyricLines++
+
+		words := a.findWords(line.Text, line.Language)
+		if len(words) == 0 {
+			continue
+		}
+
+		input.Lines[i].Explicit = true
+		explicitLine := model.ExplicitLine{LineNumber: line.LineNumber}
+		for _, word := range words {
+			explicitLine.Words = append(explicitLine.Words, model.ExplicitWord{
+				Position: word.position,
+				Text:     word.span.text,
+				Match:    word.match,
+			})
+		}
+
+		profanity.Lines = append(profanity.Lines, explicitLine)
+		profanity.Count += len(words)
+	}
+
+	profanity.Explicit = profanity.Count > 0
+	if lyricLines > 0 {
+		profanity.Score = round2(float64(len(profanity.Lines)) / float64(lyricLines))
+	}
+
+	response.Profanity = profanity
+	return nil
+}
+
+// MaskText implements TextMasker, replacing every letter of each explicit
+// word with an asterisk and leaving surrounding punctuation in place
+func (a *ProfanityAnalyzer) MaskText(text, language string) string {
+	words := a.findWords(text, language)
+	if len(words) == 0 {
+		return text
+	}
+
+	var masked strings.Builder
+	last := 0
+	for _, word := range words {
+		masked.WriteString(text[last:word.span.start])
+		masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(word.span.text)))
+		last = word.span.end
+	}
+	masked.WriteString(text[last:])
+
+	return masked.String()
+}
+
+// findWords returns the explicit words in text, checked against the list
+// for language, or every list when the language has none
+func (a *ProfanityAnalyzer) findWords(text, language string) []profaneWord {
+	entries, ok := a.lists[language]
+	if !ok {
+		entries = a.all
+	}
+
+	var found []profaneWord
+	for _, span := range wordSpans(text) {
+		if match, ok := matchProfanity(span.text, entries); ok {
+			found = append(found, profaneWord{position: span.field, span: span, match: match})
+		}
+	}
+	return found
+}
+
+// matchProfanity returns the list entry a word matches, if any
+func matchProfanity(word string, entries []profanityEntry) (string, bool) {
+	token, visible, hasLetter := normalizeProfanity(word)
+	if !hasLetter || visible < minVisibleLetters {
+		return "", false
+	}
+
+	collapsed := collapseRepeats(token)
+	stretched := hasLongRun(token)
+
+	for _, entry := range entries {
+		if matchRunes(token, entry.word, entry.prefix) {
+			return entry.text, true
+		}
+		if stretched && matchRunes(collapsed, entry.collapsed, entry.prefix) {
+			return entry.text, true
+		}
+	}
+	return "", false
+}
+
+// normalizeProfanity lowercases a word, strips accents and undoes
+// leetspeak. It also reports how many letters are not masked and whether
+// the original word has a letter at all, so numbers are never matched.
+func normalizeProfanity(word string) ([]rune, int, bool) {
+	hasLetter := false
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+
+	runes := []rune(foldAccents.Replace(strings.ToLower(word)))
+	visible := 0
+	for i, r := range runes {
+		if leet, ok := leetLetters[r]; ok {
+			runes[i] = leet
+		}
+		if !strings.ContainsRune(maskRunes, runes[i]) {
+			visible++
+		}
+	}
+
+	return runes, visible, hasLetter
+}
+
+// matchRunes compares a word with a list entry, letting mask characters in
+// the word stand for any letter
+func matchRunes(token, word []rune, prefix bool) bool {
+	if len(token) < len(word) || (!prefix && len(token) != len(word)) {
+		return false
+	}
+	for i, r := range word {
+		if token[i] != r && !strings.ContainsRune(maskRunes, token[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// collapseRepeats reduces each run of the same letter to one
+func collapseRepeats(runes []rune) []rune {
+	collapsed := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		if i == 0 || r != runes[i-1] {
+			collapsed = append(collapsed, r)
+		}
+	}
+	return collapsed
+}
+
+// hasLongRun reports whether a letter repeats three or more times in a row,
+// the sign of a stretched word such as "shiiit"
+func hasLongRun(runes []rune) bool {
+	for i := 2; i < len(runes); i++ {
+		if runes[i] == runes[i-1] && runes[i] == runes[i-2] {
+			return true
+		}
+	}
+	return false
+}
+
+// wordSpans splits text on whitespace and trims punctuation from each end
+// of every word, keeping the leetspeak and mask characters that can stand
+// for letters. Fields that are only punctuation, such as "-", yield no span
+// but still count towards the field index.
+func wordSpans(text string) []wordSpan {
+	var spans []wordSpan
+	start := -1
+	index := 0
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		field := text[start:end]
+		trimmedStart := start + len(field) - len(strings.TrimLeftFunc(field, isNotProfanityRune))
+		trimmedEnd := start + len(strings.TrimRightFunc(field, isNotProfanityRune))
+		if trimmedStart < trimmedEnd {
+			spans = append(spans, wordSpan{text: text[trimmedStart:trimmedEnd], start: trimmedStart, end: trimmedEnd, field: index})
+		}
+		index++
+		start = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			flush(i)
+		} else if start < 0 {
+			start = i
+		}
+	}
+	flush(len(text))
+
+	return spans
+}
+
+// isNotProfanityRune reports whether r is punctuation around a word rather
+// than a letter, digit or stand-in for one. A "!" only counts as a letter
+// inside a word ("sh!t"), so it is trimmed from the ends.
+func isNotProfanityRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+	return !strings.ContainsRune("@$"+maskRunes, r)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfanityAnalyzer_FindWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language string
+		matches  []string
+	}{
+		{"plain word", "Oh shit, here we go", "en", []string{"shit*"}},
+		{"inflection", "Fucking up again", "en", []string{"fuck*"}},
+		{"capitals and punctuation", "BITCH!", "en", []string{"bitch*"}},
+		{"leetspeak", "sh1t and b!tch and @ss", "en", []string{"shit*", "bitch*", "ass"}},
+		{"self-censored", "What the f*ck", "en", []string{"fuck*"}},
+		{"stretched", "Daaamn, fuuuck", "en", []string{"damn*", "fuck*"}},
+		{"accents folded", "Eres un cabron", "es", []string{"cabron*"}},
+		{"other languages checked when untagged", "Eres un cabrón", "", []string{"cabron*"}},
+		{"exact word only", "Classic assessment", "en", nil},
+		{"fully masked", "What the ****", "en", nil},
+		{"numbers", "Call 555 7734", "en", nil},
+		{"clean line", "Walking down the street", "en", nil},
+	}
+
+	analyzer := NewProfanityAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matches []string
+			for _, word := range analyzer.findWords(tt.text, tt.language) {
+				matches = append(matches, word.match)
+			}
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestProfanityAnalyzer_MaskText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"keeps punctuation", "Oh shit, here we go!", "Oh ****, here we go!"},
+		{"several words", "F*ck this sh1t", "**** this ****"},
+		{"multibyte word", "¡Qué cabrón!", "¡Qué ******!"},
+		{"clean line unchanged", "Walking down the street", "Walking down the street"},
+	}
+
+	analyzer := NewProfanityAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, analyzer.MaskText(tt.text, ""))
+		})
+	}
+}
+
+func TestProfanityAnalyzer_Analyze(t *testing.T) {
+	lines := songLines("Oh shit, shit", "Walking down the street", "", "Fucking hell", "La la la")
+	input := &AnalysisInput{Lines: lines}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewProfanityAnalyzer().Analyze(context.Background(), input, response)
+	require.NoError(t, err)
+
+	profanity := response.Profanity
+	require.NotNil(t, profanity)
+	assert.True(t, profanity.Explicit)
+	assert.Equal(t, 3, profanity.Count)
+	assert.Equal(t, 0.5, profanity.Score)
+	require.Len(t, profanity.Lines, 2)
+	assert.Equal(t, model.ExplicitLine{
+		LineNumber: lines[0].LineNumber,
+		Words: []model.ExplicitWord{
+			{Position: 1, Text: "shit", Match: "shit*"},
+			{Position: 2, Text: "shit", Match: "shit*"},
+		},
+	}, profanity.Lines[0])
+	assert.Equal(t, lines[3].LineNumber, profanity.Lines[1].LineNumber)
+
+	assert.True(t, lines[0].Explicit)
+	assert.False(t, lines[1].Explicit)
+	assert.True(t, lines[3].Explicit)
+}
+
+func TestProfanityAnalyzer_Analyze_PositionCountsBarePunctuation(t *testing.T) {
+	response := &model.SongAnalysisResponse{}
+
+	err := NewProfanityAnalyzer().Analyze(context.Background(), &AnalysisInput{Lines: songLines("Oh - shit, here we go")}, response)
+	require.NoError(t, err)
+
+	require.Len(t, response.Profanity.Lines, 1)
+	assert.Equal(t, []model.ExplicitWord{{Position: 2, Text: "shit", Match: "shit*"}}, response.Profanity.Lines[0].Words)
+}
+
+func TestProfanityAnalyzer_Analyze_Clean(t *testing.T) {
+	response := &model.SongAnalysisResponse{}
+
+	err := NewProfanityAnalyzer().Analyze(context.Background(), &AnalysisInput{Lines: songLines("Walking down the street")}, response)
+	require.NoError(t, err)
+
+	assert.Equal(t, &model.Profanity{}, response.Profanity)
+}
+
+func TestProfanityAnalyzer_ExtraLists(t *testing.T) {
+	analyzer := NewProfanityAnalyzerWithLists(map[string][]string{"en": {"frak*"}})
+
+	assert.Equal(t, "Oh ********", analyzer.MaskText("Oh frakking", "en"))
+	assert.Equal(t, "Oh ****", analyzer.MaskText("Oh shit", "en"), "bundled words are kept")
+}
+
+func TestLoadWordLists(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.txt"), []byte("# extra words\nFrak*\n\ngorram\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("ignored"), 0o644))
+
+	lists, err := LoadWordLists(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"en": {"frak*", "gorram"}}, lists)
+
+	lists, err = LoadWordLists("")
+	assert.NoError(t, err)
+	assert.Nil(t, lists)
+
+	_, err = LoadWordLists(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// bundledPronunciations is a small CMUdict-format dictionary of common lyric words
+//
+//go:embed data/pronunciations.dict
+var bundledPronunciations string
+
+// defaultPronunciations parses the bundled dictionary once on first use
+var defaultPronunciations = sync.OnceValue(func() *pronunciationDictionary {
+	return parsePronunciations(bundledPronunciations)
+})
+
+// pronunciationDictionary maps lowercase words to ARPAbet phonemes
+type pronunciationDictionary struct {
+	entries map[string][]string
+}
+
+// parsePronunciations reads CMUdict lines ("WORD  P1 P2 ..."), skipping ;;;
+// comments and keeping only the first of alternative pronunciations "WORD(2)"
+func parsePronunciations(data string) *pronunciationDictionary {
+	dict := &pronunciationDictionary{entries: make(map[string][]string)}
+
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, ";;;") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.Contains(fields[0], "(") {
+			continue
+		}
+
+		word := strings.ToLower(fields[0])
+		if _, ok := dict.entries[word]; !ok {
+			dict.entries[word] = fields[1:]
+		}
+	}
+
+	return dict
+}
+
+// lookup returns the phonemes of word, if known
+func (d *pronunciationDictionary) lookup(word string) ([]string, bool) {
+	phonemes, ok := d.entries[strings.ToLower(word)]
+	return phonemes, ok
+}
+
+// isVowelPhoneme reports whether an ARPAbet phoneme is a vowel; only vowels carry a stress digit
+func isVowelPhoneme(phoneme string) bool {
+	last := phoneme[len(phoneme)-1]
+	return last >= '0' && last <= '2'
+}
+
+// phonemeRhyme returns the phonemes from the last stressed vowel to the end,
+// without stress digits. Words rhyme when these match: "night" and "light"
+// both give "AY T".
+func phonemeRhyme(phonemes []string) string {
+	start := -1
+	for i, phoneme := range phonemes {
+		if !isVowelPhoneme(phoneme) {
+			continue
+		}
+		stress := phoneme[len(phoneme)-1]
+		if stress == '1' || stress == '2' || start < 0 {
+			start = i
+		}
+	}
+
+	if start < 0 {
+		return strings.Join(phonemes, " ")
+	}
+
+	parts := make([]string, 0, len(phonemes)-start)
+	for _, phoneme := range phonemes[start:] {
+		parts = append(parts, strings.TrimRight(phoneme, "012"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// spellingRhyme approximates the rhyming part of a word from its spelling:
+// the last vowel group and everything after it, reaching back past a silent
+// final "e" ("time" gives "ime") or a final "y" after a consonant ("crazy"
+// gives "azy", "sky" gives "y")
+func spellingRhyme(word string) string {
+	runes := []rune(strings.ToLower(word))
+	end := len(runes)
+	if end == 0 {
+		return ""
+	}
+
+	search := end
+	if end > 1 && (runes[end-1] == 'e' || runes[end-1] == 'y') && !isSpellingVowel(runes[end-2]) {
+		search = end - 2
+	}
+
+	// Find the last vowel group before the search point
+	i := search - 1
+	for i >= 0 && !isSpellingVowel(runes[i]) {
+		i--
+	}
+	if i < 0 {
+		if search < end {
+			return string(runes[end-1:])
+		}
+		return string(runes)
+	}
+	for i > 0 && isSpellingVowel(runes[i-1]) {
+		i--
+	}
+
+	return string(runes[i:])
+}
+
+// isSpellingVowel reports whether r is a vowel letter
+func isSpellingVowel(r rune) bool {
+	return strings.ContainsRune("aeiouáéíóúàèìòùäëïöüâêîôû", r)
+}
+
+// syllableCount returns the number of vowel phonemes in a pronunciation
+func syllableCount(phonemes []string) int {
+	count := 0
+	for _, phoneme := range phonemes {
+		if isVowelPhoneme(phoneme) {
+			count++
+		}
+	}
+	return count
+}
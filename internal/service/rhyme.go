@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// RhymeAnalyzer finds the end-rhyme scheme of each section. Words are
+// matched by pronunciation from the bundled dictionary, falling back to
+// their spelling when a word is not in it. The dictionary is English, so
+// lines in other languages are matched by spelling only and the result is
+// marked approximate.
+type RhymeAnalyzer struct {
+	tokenizer  Tokenizer
+	dictionary *pronunciationDictionary
+}
+
+// NewRhymeAnalyzer creates a rhyme analyzer using the bundled pronunciation dictionary
+func NewRhymeAnalyzer() *RhymeAnalyzer {
+	return &RhymeAnalyzer{
+		tokenizer:  NewUnicodeTokenizer(),
+		dictionary: defaultPronunciations(),
+	}
+}
+
+// Name implements Analyzer
+func (a *RhymeAnalyzer) Name() string {
+	return AnalyzerRhyme
+}
+
+// Analyze implements Analyzer. Each lyric line gets a rhyme group letter
+// that is shared with the lines it rhymes with in the same section.
+func (a *RhymeAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	analysis := &model.RhymeAnalysis{Schemes: []model.RhymeScheme{}, Approximate: englishDataApproximate(input)}
+	totalLines, rhymingLines := 0, 0
+
+	for _, section := range analysisSections(input, response) {
+		var indexes []int
+		for i, line := range input.Lines {
+			if line.LineNumber >= section.StartLine && line.LineNumber <= section.EndLine && line.Text != "" {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) == 0 {
+			continue
+		}
+
+		words := make([]string, len(indexes))
+		spelling := make([]bool, len(indexes))
+		for k, i := range indexes {
+			words[k] = a.endWord(input.Lines[i].Text)
+			spelling[k] = outsideEnglishData(lineLanguage(input.Lines[i], input.Language))
+		}
+
+		// Each line joins the group of the first earlier line it rhymes with
+		groups := make([]int, len(indexes))
+		groupSizes := []int{}
+		for k := range words {
+			groups[k] = -1
+			for j := 0; j < k; j++ {
+				if a.rhymes(words[j], words[k], spelling[j] || spelling[k]) {
+					groups[k] = groups[j]
+					break
+				}
+			}
+			if groups[k] < 0 {
+				groups[k] = len(groupSizes)
+				groupSizes = append(groupSizes, 0)
+			}
+			groupSizes[groups[k]]++
+		}
+
+		var scheme strings.Builder
+		for k, i := range indexes {
+			letter := schemeLetter(groups[k])
+			input.Lines[i].RhymeGroup = letter
+			scheme.WriteString(letter)
+
+			totalLines++
+			if groupSizes[groups[k]] > 1 {
+				rhymingLines++
+			}
+		}
+
+		analysis.Schemes = append(analysis.Schemes, model.RhymeScheme{
+			Label:     section.Label,
+			StartLine: section.StartLine,
+			EndLine:   section.EndLine,
+			Scheme:    scheme.String(),
+		})
+	}
+
+	if totalLines > 0 {
+		analysis.Density = round2(float64(rhymingLines) / float64(totalLines))
+	}
+
+	response.Rhyme = analysis
+	return nil
+}
+
+// endWord returns the last word of a line in lowercase
+func (a *RhymeAnalyzer) endWord(text string) string {
+	words := a.tokenizer.Tokenize(text)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[len(words)-1])
+}
+
+// rhymes reports whether two end words rhyme. Pronunciations are compared
+// when both words are in the dictionary, spellings otherwise or when
+// spellingOnly is set.
+func (a *RhymeAnalyzer) rhymes(first, second string, spellingOnly bool) bool {
+	if first == "" || second == "" {
+		return false
+	}
+	if first == second {
+		return true
+	}
+
+	if spellingOnly {
+		return spellingRhyme(first) == spellingRhyme(second)
+	}
+
+	firstPhonemes, firstKnown := a.dictionary.lookup(first)
+	secondPhonemes, secondKnown := a.dictionary.lookup(second)
+	if firstKnown && secondKnown {
+		return phonemeRhyme(firstPhonemes) == phonemeRhyme(secondPhonemes)
+	}
+
+	return spellingRhyme(first) == spellingRhyme(second)
+}
+
+// schemeLetter names the nth rhyme group: A to Z, then AA, AB and so on
+func schemeLetter(n int) string {
+	if n < 26 {
+		return string(rune('A' + n))
+	}
+	return schemeLetter(n/26-1) + string(rune('A'+n%26))
+}
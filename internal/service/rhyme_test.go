@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhonemeRhyme(t *testing.T) {
+	dict := defaultPronunciations()
+
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"night", "AY T"},
+		{"tonight", "AY T"},
+		{"baby", "EY B IY"},
+		{"forever", "EH V ER"},
+		{"yesterday", "EY"}, // secondary stress counts, so it rhymes with "day"
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			phonemes, ok := dict.lookup(tt.word)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, phonemeRhyme(phonemes))
+		})
+	}
+}
+
+func TestSpellingRhyme(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"midnight", "ight"},
+		{"shine", "ine"},
+		{"lazy", "azy"},
+		{"sky", "y"},
+		{"corazón", "ón"},
+		{"alone", "one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.expected, spellingRhyme(tt.word))
+		})
+	}
+}
+
+func TestParsePronunciations(t *testing.T) {
+	dict := parsePronunciations(";;; comment\nREAD  R IY1 D\nREAD(2)  R EH1 D\n\nBAD\n")
+
+	phonemes, ok := dict.lookup("Read")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"R", "IY1", "D"}, phonemes)
+
+	_, ok = dict.lookup("bad")
+	assert.False(t, ok)
+}
+
+func TestRhymeAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []model.LyricLine
+		sections []model.Section
+		schemes  []model.RhymeScheme
+		groups   []string
+		density  float64
+	}{
+		{
+			name:  "couplets split at a break",
+			lines: songLines("I see the light", "We dance tonight", "Our hearts are free", "Just you and me", "", "Take my hand", "You understand"),
+			schemes: []model.RhymeScheme{
+				{StartLine: 1, EndLine: 4, Scheme: "AABB"},
+				{StartLine: 6, EndLine: 7, Scheme: "AA"},
+			},
+			groups:  []string{"A", "A", "B", "B", "", "A", "A"},
+			density: 1,
+		},
+		{
+			name:  "alternating rhymes in labelled sections",
+			lines: songLines("Waiting in the rain", "Calling out your name", "Lost in all the pain", "Nothing is the same", "Nothing rhymes here"),
+			sections: []model.Section{
+				{Type: model.SectionVerse, Label: "Verse 1", StartLine: 1, EndLine: 4},
+				{Type: model.SectionOutro, Label: "Outro", StartLine: 5, EndLine: 5},
+			},
+			schemes: []model.RhymeScheme{
+				{Label: "Verse 1", StartLine: 1, EndLine: 4, Scheme: "ABAB"},
+				{Label: "Outro", StartLine: 5, EndLine: 5, Scheme: "A"},
+			},
+			groups:  []string{"A", "B", "A", "B", "A"},
+			density: 0.8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Lines: tt.lines, Sections: tt.sections}
+			response := &model.SongAnalysisResponse{}
+
+			err := NewRhymeAnalyzer().Analyze(context.Background(), input, response)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.schemes, response.Rhyme.Schemes)
+			assert.Equal(t, tt.density, response.Rhyme.Density)
+
+			var groups []string
+			for _, line := range input.Lines {
+				groups = append(groups, line.RhymeGroup)
+			}
+			assert.Equal(t, tt.groups, groups)
+		})
+	}
+}
+
+func TestRhymeAnalyzer_Analyze_NotEnglish(t *testing.T) {
+	// "through" and "you" rhyme by pronunciation but not by spelling
+	lines := songLines("I walk through", "I think of you")
+
+	t.Run("english uses pronunciations", func(t *testing.T) {
+		response := &model.SongAnalysisResponse{}
+
+		err := NewRhymeAnalyzer().Analyze(context.Background(), &AnalysisInput{Lines: lines, Language: "en"}, response)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "AA", response.Rhyme.Schemes[0].Scheme)
+		assert.False(t, response.Rhyme.Approximate)
+	})
+
+	t.Run("other languages are matched by spelling and marked", func(t *testing.T) {
+		response := &model.SongAnalysisResponse{}
+
+		err := NewRhymeAnalyzer().Analyze(context.Background(), &AnalysisInput{Lines: lines, Language: "es"}, response)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "AB", response.Rhyme.Schemes[0].Scheme)
+		assert.True(t, response.Rhyme.Approximate)
+	})
+}
+
+func TestSchemeLetter(t *testing.T) {
+	assert.Equal(t, "A", schemeLetter(0))
+	assert.Equal(t, "Z", schemeLetter(25))
+	assert.Equal(t, "AA", schemeLetter(26))
+	assert.Equal(t, "AB", schemeLetter(27))
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// sectionKeywords maps Genius-style header keywords to section types
+var sectionKeywords = map[string]string{
+	"intro":        model.SectionIntro,
+	"verse":        model.SectionVerse,
+	"pre-chorus":   model.SectionPreChorus,
+	"prechorus":    model.SectionPreChorus,
+	"chorus":       model.SectionChorus,
+	"refrain":      model.SectionChorus,
+	"hook":         model.SectionChorus,
+	"post-chorus":  model.SectionPostChorus,
+	"postchorus":   model.SectionPostChorus,
+	"bridge":       model.SectionBridge,
+	"interlude":    model.SectionInterlude,
+	"instrumental": model.SectionInterlude,
+	"breakdown":    model.SectionInterlude,
+	"outro":        model.SectionOutro,
+	"part":         model.SectionOther,
+	"skit":         model.SectionOther,
+	"drop":         model.SectionOther,
+}
+
+// parseSectionHeader recognises headers such as [Chorus] or [Verse 2: Artist].
+// Returns nil for lines that are not section headers.
+func (p *Parser) parseSectionHeader(line string) *model.Section {
+	matches := p.sectionHeaderRegex.FindStringSubmatch(line)
+	if len(matches) != 4 {
+		return nil
+	}
+
+	keyword := strings.ToLower(strings.Join(strings.Fields(matches[1]), "-"))
+	sectionType, ok := sectionKeywords[keyword]
+	if !ok {
+		return nil
+	}
+
+	label := strings.TrimSpace(matches[1])
+	if matches[2] != "" {
+		label += " " + matches[2]
+	}
+
+	return &model.Section{
+		Type:      sectionType,
+		Label:     label,
+		Performer: strings.TrimSpace(matches[3]),
+	}
+}
+
+// closeSection sets the end line of the open section and appends it when it
+// holds at least one line; placeholder headers with no lyrics are dropped
+func closeSection(sections []model.Section, open *model.Section, lastLine int) []model.Section {
+	if open == nil || lastLine < open.StartLine {
+		return sections
+	}
+
+	open.EndLine = lastLine
+	return append(sections, *open)
+}
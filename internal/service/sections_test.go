@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParsePlainLyricsWithSections(t *testing.T) {
+	input := `
+		Spoken intro line
+		[Verse 1: Kendrick Lamar]
+		First verse line
+		Second verse line
+
+		[Chorus]
+		Chorus line
+		[Pre Chorus]
+		[Verse 2: SZA & Kendrick Lamar]
+		Third verse line
+		[Bridge]
+	`
+
+	parser := NewParser()
+	lines, sections, err := parser.ParsePlainLyricsWithSections(input)
+
+	assert.NoError(t, err)
+
+	// Headers are removed from the lyric lines
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "Spoken intro line", lines[0].Text)
+	assert.Equal(t, "First verse line", lines[1].Text)
+	assert.Equal(t, 2, lines[1].LineNumber)
+	assert.Equal(t, "Chorus line", lines[3].Text)
+
+	// Empty placeholder sections ([Pre Chorus], [Bridge]) are dropped
+	assert.Equal(t, []model.Section{
+		{Type: model.SectionVerse, Label: "Verse 1", Performer: "Kendrick Lamar", StartLine: 2, EndLine: 3},
+		{Type: model.SectionChorus, Label: "Chorus", StartLine: 4, EndLine: 4},
+		{Type: model.SectionVerse, Label: "Verse 2", Performer: "SZA & Kendrick Lamar", StartLine: 5, EndLine: 5},
+	}, sections)
+}
+
+func TestParser_ParseSectionHeader(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected *model.Section
+	}{
+		{"[Chorus]", &model.Section{Type: model.SectionChorus, Label: "Chorus"}},
+		{"[Hook]", &model.Section{Type: model.SectionChorus, Label: "Hook"}},
+		{"[Pre-Chorus]", &model.Section{Type: model.SectionPreChorus, Label: "Pre-Chorus"}},
+		{"[Post Chorus: Drake]", &model.Section{Type: model.SectionPostChorus, Label: "Post Chorus", Performer: "Drake"}},
+		{"[ Outro ]", &model.Section{Type: model.SectionOutro, Label: "Outro"}},
+		{"[Intro: Both]", &model.Section{Type: model.SectionIntro, Label: "Intro", Performer: "Both"}},
+		{"[Produced by Someone]", nil},
+		{"[00:10.00] Lyric", nil},
+		{"Chorus", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			parser := NewParser()
+			assert.Equal(t, tt.expected, parser.parseSectionHeader(tt.line))
+		})
+	}
+}
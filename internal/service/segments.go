@@ -0,0 +1,256 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// maxIntroLines is the longest opening stanza still treated as an intro
+const maxIntroLines = 2
+
+// formLetters abbreviates section types in the compact song form
+var formLetters = map[string]string{
+	model.SectionIntro:      "I",
+	model.SectionVerse:      "V",
+	model.SectionPreChorus:  "P",
+	model.SectionChorus:     "C",
+	model.SectionPostChorus: "T",
+	model.SectionBridge:     "B",
+	model.SectionInterlude:  "S",
+	model.SectionOutro:      "O",
+	model.SectionOther:      "X",
+}
+
+// span is a range of indexes into the song's lines
+type span struct {
+	start, end  int // inclusive
+	sectionType string
+}
+
+// segmentSong splits a song without section headers into labelled sections.
+// Chorus occurrences anchor the layout; the lines between them become
+// verses, with a repeated lead-in marked as pre-chorus, an opening stanza of
+// at most two lines as intro, the last stretch between choruses as bridge
+// once two verses have been heard, and the lines after the final chorus as
+// outro. Back-to-back chorus occurrences form one section. Songs without a
+// chorus are split into verses at instrumental breaks.
+// Keys are the line keys the chorus was detected with.
+func (cd *ChorusDetector) segmentSong(lines []model.LyricLine, chorus *model.Chorus, keys []int) []model.Section {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(lines))
+	for i, line := range lines {
+		index[line.LineNumber] = i
+	}
+
+	// Chorus occurrences and the gaps between them, in song order
+	var spans []span
+	next := 0
+	if chorus != nil && chorus.Detected {
+		for _, occurrence := range chorus.Instances {
+			start, end := index[occurrence.StartLine], index[occurrence.EndLine]
+			before := len(spans)
+			spans = appendGap(spans, lines, next, start-1)
+			next = end + 1
+
+			// A chorus repeated back to back is sung as one section
+			if len(spans) == before && before > 0 && spans[before-1].sectionType == model.SectionChorus {
+				spans[before-1].end = end
+				continue
+			}
+			spans = append(spans, span{start: start, end: end, sectionType: model.SectionChorus})
+		}
+	}
+	spans = appendGap(spans, lines, next, len(lines)-1)
+
+	spans = splitPreChoruses(spans, keys)
+	spans = labelGaps(spans, lines)
+
+	var sections []model.Section
+	counts := make(map[string]int)
+	for _, s := range spans {
+		counts[s.sectionType]++
+		sections = append(sections, model.Section{
+			Type:      s.sectionType,
+			Label:     sectionLabel(s.sectionType, counts[s.sectionType]),
+			Performer: sharedSinger(lines[s.start : s.end+1]),
+			StartLine: lines[s.start].LineNumber,
+			EndLine:   lines[s.end].LineNumber,
+		})
+	}
+
+	return sections
+}
+
+// appendGap adds the non-chorus lines from start to end, trimmed of breaks.
+// Gaps are left untyped until labelGaps decides what they are.
+func appendGap(spans []span, lines []model.LyricLine, start, end int) []span {
+	for start <= end && lines[start].IsBreak {
+		start++
+	}
+	for end >= start && lines[end].IsBreak {
+		end--
+	}
+	if start > end {
+		return spans
+	}
+	return append(spans, span{start: start, end: end})
+}
+
+// splitPreChoruses marks the lines a gap shares with another gap right
+// before a chorus as a pre-chorus
+func splitPreChoruses(spans []span, keys []int) []span {
+	var leadIns []int
+	for i := 0; i+1 < len(spans); i++ {
+		if spans[i].sectionType == "" && spans[i+1].sectionType == model.SectionChorus {
+			leadIns = append(leadIns, i)
+		}
+	}
+
+	suffixes := make(map[int]int)
+	for _, a := range leadIns {
+		for _, b := range leadIns {
+			if a != b {
+				suffixes[a] = max(suffixes[a], commonSuffix(keys, spans[a], spans[b]))
+			}
+		}
+	}
+
+	var result []span
+	for i, s := range spans {
+		n := suffixes[i]
+		if n == 0 {
+			result = append(result, s)
+			continue
+		}
+
+		if split := s.end - n + 1; split > s.start {
+			result = append(result, span{start: s.start, end: split - 1})
+			s.start = split
+		}
+		s.sectionType = model.SectionPreChorus
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// commonSuffix counts the matching lines at the end of two spans
+func commonSuffix(keys []int, a, b span) int {
+	n := 0
+	for a.end-n >= a.start && b.end-n >= b.start {
+		if keys[a.end-n] != keys[b.end-n] {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// labelGaps names the untyped spans intro, verse, bridge or outro
+func labelGaps(spans []span, lines []model.LyricLine) []span {
+	lastChorus := -1
+	for i, s := range spans {
+		if s.sectionType == model.SectionChorus {
+			lastChorus = i
+		}
+	}
+
+	var result []span
+	verses := 0
+	for i, s := range spans {
+		if s.sectionType != "" {
+			result = append(result, s)
+			continue
+		}
+
+		switch {
+		case lastChorus >= 0 && i > lastChorus:
+			s.sectionType = model.SectionOutro
+		case lastChorus >= 0 && verses >= 2 && isLastGapBefore(spans, i, lastChorus):
+			s.sectionType = model.SectionBridge
+		default:
+			// Verses are split at instrumental breaks; a short opening stanza is the intro
+			for k, stanza := range splitStanzas(lines, s) {
+				stanza.sectionType = model.SectionVerse
+				if i == 0 && k == 0 && stanza.end-stanza.start+1 <= maxIntroLines && stanza.end < s.end {
+					stanza.sectionType = model.SectionIntro
+				} else {
+					verses++
+				}
+				result = append(result, stanza)
+			}
+			continue
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// isLastGapBefore reports whether the span at i is the last untyped span
+// before the final chorus
+func isLastGapBefore(spans []span, i, lastChorus int) bool {
+	for k := i + 1; k < lastChorus; k++ {
+		if spans[k].sectionType == "" {
+			return false
+		}
+	}
+	return i < lastChorus
+}
+
+// splitStanzas splits a span at instrumental break lines
+func splitStanzas(lines []model.LyricLine, s span) []span {
+	var stanzas []span
+	start := s.start
+	for i := s.start; i <= s.end; i++ {
+		if lines[i].IsBreak {
+			stanzas = appendGap(stanzas, lines, start, i-1)
+			start = i + 1
+		}
+	}
+	return appendGap(stanzas, lines, start, s.end)
+}
+
+// sectionLabel builds a readable label such as "Verse 2" or "Chorus"
+func sectionLabel(sectionType string, n int) string {
+	words := strings.Split(sectionType, "-")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	label := strings.Join(words, "-")
+
+	if sectionType == model.SectionVerse {
+		return fmt.Sprintf("%s %d", label, n)
+	}
+	return label
+}
+
+// sharedSinger returns the singer of every lyric line, or "" if they differ
+func sharedSinger(lines []model.LyricLine) string {
+	singer := ""
+	for _, line := range lines {
+		if line.IsBreak {
+			continue
+		}
+		if line.Singer == "" || (singer != "" && line.Singer != singer) {
+			return ""
+		}
+		singer = line.Singer
+	}
+	return singer
+}
+
+// songForm abbreviates the section sequence, e.g. "V-C-V-C-B-C"
+func songForm(sections []model.Section) string {
+	letters := make([]string, 0, len(sections))
+	for _, section := range sections {
+		letters = append(letters, formLetters[section.Type])
+	}
+	return strings.Join(letters, "-")
+}
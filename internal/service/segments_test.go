@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChorusDetector_SegmentSong(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []model.LyricLine
+		form     string
+		sections []model.Section
+	}{
+		{
+			name: "verse chorus bridge",
+			lines: songLines(
+				"First verse opens here",
+				"First verse goes on",
+				"This is the chorus now",
+				"Everybody sing along",
+				"Second verse begins",
+				"Second verse continues",
+				"This is the chorus now",
+				"Everybody sing along",
+				"Something new for the bridge",
+				"This is the chorus now",
+				"Everybody sing along",
+			),
+			form: "V-C-V-C-B-C",
+			sections: []model.Section{
+				{Type: model.SectionVerse, Label: "Verse 1", StartLine: 1, EndLine: 2},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 3, EndLine: 4},
+				{Type: model.SectionVerse, Label: "Verse 2", StartLine: 5, EndLine: 6},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 7, EndLine: 8},
+				{Type: model.SectionBridge, Label: "Bridge", StartLine: 9, EndLine: 9},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 10, EndLine: 11},
+			},
+		},
+		{
+			name: "chorus repeated back to back",
+			lines: songLines(
+				"First verse opens here",
+				"First verse goes on",
+				"This is the chorus now",
+				"Everybody sing along",
+				"This is the chorus now",
+				"Everybody sing along",
+				"Second verse begins",
+				"Second verse continues",
+				"This is the chorus now",
+				"Everybody sing along",
+				"",
+				"This is the chorus now",
+				"Everybody sing along",
+			),
+			form: "V-C-V-C",
+			sections: []model.Section{
+				{Type: model.SectionVerse, Label: "Verse 1", StartLine: 1, EndLine: 2},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 3, EndLine: 6},
+				{Type: model.SectionVerse, Label: "Verse 2", StartLine: 7, EndLine: 8},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 9, EndLine: 13},
+			},
+		},
+		{
+			name: "intro, pre-chorus and outro",
+			lines: songLines(
+				"Ooh yeah",
+				"",
+				"Morning light on the window",
+				"Coffee going cold",
+				"Here it comes again",
+				"Hold on to the night",
+				"Never let it go",
+				"Evening on the highway",
+				"Here it comes again",
+				"Hold on to the night",
+				"Never let it go",
+				"Something new for the bridge",
+				"Hold on to the night",
+				"Never let it go",
+				"Fading out now",
+			),
+			form: "I-V-P-C-V-P-C-B-C-O",
+			sections: []model.Section{
+				{Type: model.SectionIntro, Label: "Intro", StartLine: 1, EndLine: 1},
+				{Type: model.SectionVerse, Label: "Verse 1", StartLine: 3, EndLine: 4},
+				{Type: model.SectionPreChorus, Label: "Pre-Chorus", StartLine: 5, EndLine: 5},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 6, EndLine: 7},
+				{Type: model.SectionVerse, Label: "Verse 2", StartLine: 8, EndLine: 8},
+				{Type: model.SectionPreChorus, Label: "Pre-Chorus", StartLine: 9, EndLine: 9},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 10, EndLine: 11},
+				{Type: model.SectionBridge, Label: "Bridge", StartLine: 12, EndLine: 12},
+				{Type: model.SectionChorus, Label: "Chorus", StartLine: 13, EndLine: 14},
+				{Type: model.SectionOutro, Label: "Outro", StartLine: 15, EndLine: 15},
+			},
+		},
+		{
+			name:  "no chorus splits verses at breaks",
+			lines: songLines("One line here", "Two lines here", "Three lines here", "", "", "Four lines here"),
+			form:  "V-V",
+			sections: []model.Section{
+				{Type: model.SectionVerse, Label: "Verse 1", StartLine: 1, EndLine: 3},
+				{Type: model.SectionVerse, Label: "Verse 2", StartLine: 6, EndLine: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewChorusDetector()
+			keys, err := detector.lineKeys(context.Background(), tt.lines)
+			assert.NoError(t, err)
+			chorus := detector.detectChorus(tt.lines, keys)
+
+			sections := detector.segmentSong(tt.lines, chorus, keys)
+
+			assert.Equal(t, tt.sections, sections)
+			assert.Equal(t, tt.form, songForm(sections))
+		})
+	}
+}
+
+func TestChorusDetector_SegmentSong_SharedSinger(t *testing.T) {
+	lines := songLines("Hello from me", "Still me here")
+	lines[0].Singer = "Alice"
+	lines[1].Singer = "Alice"
+
+	detector := NewChorusDetector()
+	keys, err := detector.lineKeys(context.Background(), lines)
+	assert.NoError(t, err)
+
+	sections := detector.segmentSong(lines, &model.Chorus{Detected: false}, keys)
+
+	assert.Len(t, sections, 1)
+	assert.Equal(t, "Alice", sections[0].Performer)
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// VADER scoring constants
+const (
+	// boosterIncrement is how far an intensifier pushes a word's valence
+	boosterIncrement = 0.293
+
+	// negationScalar flips and softens a negated word, so "not happy" is
+	// less negative than "sad"
+	negationScalar = -0.74
+
+	// negationWindow is how many preceding words can negate or intensify a word
+	negationWindow = 3
+
+	// normalizeAlpha maps summed valence onto -1..1
+	normalizeAlpha = 15
+
+	// polarityThreshold is the score beyond which text is positive or negative
+	polarityThreshold = 0.05
+)
+
+// sentimentWindowMs is the width of each point on the sentiment timeline
+const sentimentWindowMs = 10000
+
+// boosterWords strengthen (positive) or soften (negative) the word after them
+var boosterWords = map[string]float64{
+	"absolutely": boosterIncrement, "completely": boosterIncrement, "deeply": boosterIncrement,
+	"especially": boosterIncrement, "extremely": boosterIncrement, "incredibly": boosterIncrement,
+	"really": boosterIncrement, "so": boosterIncrement, "such": boosterIncrement,
+	"super": boosterIncrement, "too": boosterIncrement, "totally": boosterIncrement,
+	"truly": boosterIncrement, "utterly": boosterIncrement, "very": boosterIncrement,
+	"almost": -boosterIncrement, "barely": -boosterIncrement, "hardly": -boosterIncrement,
+	"kinda": -boosterIncrement, "scarcely": -boosterIncrement, "slightly": -boosterIncrement,
+	"somewhat": -boosterIncrement, "sorta": -boosterIncrement,
+}
+
+// negationWords negate the words after them; any word ending in "n't" does too
+var negationWords = map[string]bool{
+	"aint": true, "arent": true, "cannot": true, "cant": true, "didnt": true,
+	"doesnt": true, "dont": true, "isnt": true, "neither": true, "never": true,
+	"no": true, "nobody": true, "none": true, "nor": true, "not": true,
+	"nothing": true, "nowhere": true, "wasnt": true, "without": true,
+	"wont": true, "wouldnt": true,
+}
+
+// timedScore is the sentiment of a line sung over a known time
+type timedScore struct {
+	startMs int
+	endMs   int
+	score   float64
+}
+
+// SentimentAnalyzer scores mood and emotions per line, per section and for
+// the whole song with a bundled VADER-style lexicon, handling negation
+// ("not happy") and intensifiers ("so happy"). The lexicon is English, so
+// lines tagged with another language are not scored and the result is
+// marked approximate.
+type SentimentAnalyzer struct {
+	tokenizer Tokenizer
+	lexicon   *sentimentLexicon
+}
+
+// NewSentimentAnalyzer creates a sentiment analyzer using the bundled lexicon
+func NewSentimentAnalyzer() *SentimentAnalyzer {
+	return &SentimentAnalyzer{
+		tokenizer: NewUnicodeTokenizer(),
+		lexicon:   defaultSentimentLexicon(),
+	}
+}
+
+// Name implements Analyzer
+func (a *SentimentAnalyzer) Name() string {
+	return AnalyzerSentiment
+}
+
+// Analyze implements Analyzer
+func (a *SentimentAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	sentiment := &model.Sentiment{Lines: []model.LineSentiment{}, Approximate: englishDataApproximate(input)}
+	var timed []timedScore
+
+	for _, line := range input.Lines {
+		if line.IsBreak || line.Text == "" || outsideEnglishData(lineLanguage(line, input.Language)) {
+			continue
+		}
+
+		lineSentiment := a.scoreLine(line)
+		sentiment.Lines = append(sentiment.Lines, lineSentiment)
+
+		if line.StartMs != nil && line.EndMs != nil && *line.EndMs > *line.StartMs {
+			timed = append(timed, timedScore{startMs: *line.StartMs, endMs: *line.EndMs, score: lineSentiment.Score})
+		}
+	}
+
+	sentiment.Score, sentiment.Emotions = combineSentiment(sentiment.Lines)
+	sentiment.Polarity = polarity(sentiment.Score)
+
+	for _, section := range analysisSections(input, response) {
+		var inSection []model.LineSentiment
+		for _, line := range sentiment.Lines {
+			if line.LineNumber >= section.StartLine && line.LineNumber <= section.EndLine {
+				inSection = append(inSection, line)
+			}
+		}
+
+		sectionSentiment := model.SectionSentiment{
+			Label:     section.Label,
+			StartLine: section.StartLine,
+			EndLine:   section.EndLine,
+		}
+		sectionSentiment.Score, sectionSentiment.Emotions = combineSentiment(inSection)
+		sectionSentiment.Polarity = polarity(sectionSentiment.Score)
+		sentiment.Sections = append(sentiment.Sections, sectionSentiment)
+	}
+
+	sentiment.Timeline = sentimentTimeline(timed)
+
+	response.Sentiment = sentiment
+	return nil
+}
+
+// scoreLine sums the valence of each lexicon word in a line, adjusted by the
+// intensifiers and negations before it, and normalizes the sum to -1..1.
+// Negated words contribute no emotions.
+func (a *SentimentAnalyzer) scoreLine(line model.LyricLine) model.LineSentiment {
+	words := a.tokenizer.Tokenize(line.Text)
+	for i, word := range words {
+		words[i] = strings.ReplaceAll(strings.ToLower(word), "’", "'")
+	}
+
+	sum := 0.0
+	var emotions []string
+	for i, word := range words {
+		entry, ok := a.lexicon.lookup(word)
+		if !ok {
+			continue
+		}
+
+		valence := entry.valence
+		negated := false
+		for k := 1; k <= negationWindow && i-k >= 0; k++ {
+			previous := words[i-k]
+			if boost, ok := boosterWords[previous]; ok && valence != 0 {
+				// Intensifiers further away count for less
+				scaled := boost * (1 - 0.05*float64(k-1))
+				if valence < 0 {
+					scaled = -scaled
+				}
+				valence += scaled
+			}
+			if isNegation(previous) {
+				negated = true
+			}
+		}
+
+		if negated {
+			valence *= negationScalar
+		} else {
+			for _, emotion := range entry.emotions {
+				if !slices.Contains(emotions, emotion) {
+					emotions = append(emotions, emotion)
+				}
+			}
+		}
+
+		sum += valence
+	}
+
+	slices.Sort(emotions)
+	score := round2(sum / math.Sqrt(sum*sum+normalizeAlpha))
+	return model.LineSentiment{
+		LineNumber: line.LineNumber,
+		Score:      score,
+		Polarity:   polarity(score),
+		Emotions:   emotions,
+	}
+}
+
+// isNegation reports whether a lowercase word negates what follows it
+func isNegation(word string) bool {
+	return negationWords[word] || strings.HasSuffix(word, "n't")
+}
+
+// combineSentiment averages line scores and counts the lines expressing each emotion
+func combineSentiment(lines []model.LineSentiment) (float64, map[string]int) {
+	if len(lines) == 0 {
+		return 0, nil
+	}
+
+	total := 0.0
+	var emotions map[string]int
+	for _, line := range lines {
+		total += line.Score
+		for _, emotion := range line.Emotions {
+			if emotions == nil {
+				emotions = make(map[string]int)
+			}
+			emotions[emotion]++
+		}
+	}
+
+	return round2(total / float64(len(lines))), emotions
+}
+
+// polarity labels a score positive, negative or neutral
+func polarity(score float64) string {
+	switch {
+	case score >= polarityThreshold:
+		return model.PolarityPositive
+	case score <= -polarityThreshold:
+		return model.PolarityNegative
+	default:
+		return model.PolarityNeutral
+	}
+}
+
+// sentimentTimeline averages the scores of the lines sung in each fixed
+// window of the song, weighted by how long each is sung within it.
+// Windows without singing are neutral.
+func sentimentTimeline(timed []timedScore) []model.SentimentPoint {
+	songEnd := 0
+	for _, line := range timed {
+		songEnd = max(songEnd, line.endMs)
+	}
+
+	var points []model.SentimentPoint
+	for windowStart := 0; windowStart < songEnd; windowStart += sentimentWindowMs {
+		windowEnd := min(windowStart+sentimentWindowMs, songEnd)
+		weighted, sungMs := 0.0, 0
+
+		for _, line := range timed {
+			overlap := min(line.endMs, windowEnd) - max(line.startMs, windowStart)
+			if overlap <= 0 {
+				continue
+			}
+			weighted += float64(overlap) * line.score
+			sungMs += overlap
+		}
+
+		point := model.SentimentPoint{StartMs: windowStart, EndMs: windowEnd}
+		if sungMs > 0 {
+			point.Score = round2(weighted / float64(sungMs))
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentimentAnalyzer_ScoreLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		score    float64
+		polarity string
+		emotions []string
+	}{
+		{"positive word", "I am happy", 0.57, model.PolarityPositive, []string{"joy", "trust"}},
+		{"negated", "I am not happy", -0.46, model.PolarityNegative, nil},
+		{"negated contraction", "Don't cry", 0.37, model.PolarityPositive, nil},
+		{"negation within three words", "Never been so happy", -0.5, model.PolarityNegative, nil},
+		{"intensifier", "I am so happy", 0.61, model.PolarityPositive, []string{"joy", "trust"}},
+		{"dampener", "I am barely happy", 0.53, model.PolarityPositive, []string{"joy", "trust"}},
+		{"stem match", "Loving you", 0.64, model.PolarityPositive, []string{"joy", "trust"}},
+		{"mixed", "I love you but I hate the pain", -0.42, model.PolarityNegative, []string{"anger", "disgust", "fear", "joy", "sadness", "trust"}},
+		{"no lexicon words", "Walking down the street", 0, model.PolarityNeutral, nil},
+	}
+
+	analyzer := NewSentimentAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyzer.scoreLine(model.LyricLine{LineNumber: 1, Text: tt.text})
+			assert.Equal(t, tt.score, got.Score)
+			assert.Equal(t, tt.polarity, got.Polarity)
+			assert.Equal(t, tt.emotions, got.Emotions)
+		})
+	}
+}
+
+func TestSentimentAnalyzer_Analyze_Synced(t *testing.T) {
+	parser := NewParser()
+	lines, err := parser.ParseSyncedLyrics(`[00:00.00] I am so happy
+[00:05.00] Loving you
+[00:10.00]
+[00:12.00] I hate the pain
+[00:16.00] I cry alone`)
+	require.NoError(t, err)
+	assignEndTimes(lines, 20000)
+
+	input := &AnalysisInput{Lines: lines, Synced: true}
+	response := &model.SongAnalysisResponse{}
+
+	err = NewSentimentAnalyzer().Analyze(context.Background(), input, response)
+	require.NoError(t, err)
+
+	sentiment := response.Sentiment
+	require.Len(t, sentiment.Lines, 4)
+	assert.Equal(t, 4, sentiment.Lines[2].LineNumber)
+	assert.Equal(t, model.PolarityNegative, sentiment.Lines[2].Polarity)
+	assert.Equal(t, map[string]int{"anger": 1, "disgust": 1, "fear": 2, "joy": 2, "sadness": 2, "trust": 2}, sentiment.Emotions)
+
+	// Sections fall back to stanzas split at the break: a happy one and a sad one
+	require.Len(t, sentiment.Sections, 2)
+	assert.Equal(t, 0.63, sentiment.Sections[0].Score)
+	assert.Equal(t, model.PolarityPositive, sentiment.Sections[0].Polarity)
+	assert.Equal(t, -0.71, sentiment.Sections[1].Score)
+	assert.Equal(t, model.PolarityNegative, sentiment.Sections[1].Polarity)
+	assert.Equal(t, -0.04, sentiment.Score)
+	assert.Equal(t, model.PolarityNeutral, sentiment.Polarity)
+
+	// 10-second windows: the happy stanza, then the sad one after the break
+	assert.Equal(t, []model.SentimentPoint{
+		{StartMs: 0, EndMs: 10000, Score: 0.63},
+		{StartMs: 10000, EndMs: 20000, Score: -0.71},
+	}, sentiment.Timeline)
+}
+
+func TestSentimentAnalyzer_Analyze_Plain(t *testing.T) {
+	input := &AnalysisInput{Lines: songLines("Walking down the street", "Counting cars")}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewSentimentAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	assert.Zero(t, response.Sentiment.Score)
+	assert.Equal(t, model.PolarityNeutral, response.Sentiment.Polarity)
+	assert.Nil(t, response.Sentiment.Emotions)
+	assert.Nil(t, response.Sentiment.Timeline)
+	assert.Len(t, response.Sentiment.Lines, 2)
+	assert.False(t, response.Sentiment.Approximate)
+}
+
+func TestSentimentAnalyzer_Analyze_SkipsOtherLanguages(t *testing.T) {
+	lines := songLines("I love you", "Te quiero, mi amor")
+	lines[0].Language = "en"
+	lines[1].Language = "es"
+	input := &AnalysisInput{Lines: lines, Language: "en"}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewSentimentAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	require.Len(t, response.Sentiment.Lines, 1)
+	assert.Equal(t, 1, response.Sentiment.Lines[0].LineNumber)
+	assert.True(t, response.Sentiment.Approximate)
+}
+
+func TestSentimentAnalyzer_Analyze_NotEnglish(t *testing.T) {
+	lines := songLines("Te quiero, mi amor", "Bailando bajo la luna")
+	for i := range lines {
+		lines[i].Language = "es"
+	}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewSentimentAnalyzer().Analyze(context.Background(), &AnalysisInput{Lines: lines, Language: "es"}, response)
+
+	require.NoError(t, err)
+	assert.Empty(t, response.Sentiment.Lines)
+	assert.True(t, response.Sentiment.Approximate, "an unscored song is not reported as a plain neutral result")
+}
+
+func TestParseSentimentLexicon(t *testing.T) {
+	lexicon := parseSentimentLexicon("# comment\nglad\t2.0\tjoy\nmeh\tnope\nugh -1.5\n")
+
+	entry, ok := lexicon.lookup("glad")
+	require.True(t, ok)
+	assert.Equal(t, sentimentEntry{valence: 2.0, emotions: []string{"joy"}}, entry)
+
+	entry, ok = lexicon.lookup("ugh")
+	require.True(t, ok)
+	assert.Equal(t, -1.5, entry.valence)
+
+	_, ok = lexicon.lookup("meh")
+	assert.False(t, ok)
+}
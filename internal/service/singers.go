@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// extractSinger splits a leading speaker marker from lyric text.
+// Recognises enhanced LRC voices (v1:, v2:), duet markers (M:, F:, D:) and
+// parenthesised attributions such as (Beyoncé:). Returns an empty singer
+// when the text has no marker.
+func (p *Parser) extractSinger(text string) (string, string) {
+	matches := p.singerRegex.FindStringSubmatch(text)
+	if len(matches) != 4 {
+		return "", text
+	}
+
+	singer := matches[1]
+	if singer == "" {
+		singer = strings.TrimSpace(matches[2])
+	}
+
+	return singer, strings.TrimSpace(matches[3])
+}
+
+// singerBreakdown summarises lines and words per singer in order of first
+// appearance. Share is the singer's fraction of all words in the song.
+// Returns nil when no line is attributed to a singer.
+func singerBreakdown(lines []model.LyricLine, totalWords int) []model.SingerStats {
+	var breakdown []model.SingerStats
+	index := make(map[string]int)
+
+	for _, line := range lines {
+		if line.Singer == "" {
+			continue
+		}
+
+		i, ok := index[line.Singer]
+		if !ok {
+			i = len(breakdown)
+			index[line.Singer] = i
+			breakdown = append(breakdown, model.SingerStats{Singer: line.Singer})
+		}
+
+		breakdown[i].Lines++
+		breakdown[i].Words += line.WordCount
+	}
+
+	if totalWords > 0 {
+		for i := range breakdown {
+			breakdown[i].Share = round2(float64(breakdown[i].Words) / float64(totalWords))
+		}
+	}
+
+	return breakdown
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ExtractSinger(t *testing.T) {
+	tests := []struct {
+		input    string
+		singer   string
+		expected string
+	}{
+		{"v1: Hello there", "v1", "Hello there"},
+		{"v2:Hi", "v2", "Hi"},
+		{"M: I want you", "M", "I want you"},
+		{"F: I need you", "F", "I need you"},
+		{"(Beyoncé:) Say my name", "Beyoncé", "Say my name"},
+		{"(oh yeah) ad-lib", "", "(oh yeah) ad-lib"},
+		{"Mama: don't cry", "", "Mama: don't cry"},
+		{"No marker", "", "No marker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parser := NewParser()
+			singer, text := parser.extractSinger(tt.input)
+
+			assert.Equal(t, tt.singer, singer)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+}
+
+func TestParser_SingerAttribution(t *testing.T) {
+	parser := NewParser()
+
+	t.Run("enhanced LRC voices", func(t *testing.T) {
+		lines, err := parser.ParseSyncedLyrics("[00:10.00]v1: <00:10.00>Hello <00:10.50>you\n[00:12.00]v2: Hi back")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", lines[0].Singer)
+		assert.Equal(t, "Hello you", lines[0].Text)
+		assert.Len(t, lines[0].Words, 2)
+		assert.Equal(t, "v2", lines[1].Singer)
+		assert.Equal(t, "Hi back", lines[1].Text)
+	})
+
+	t.Run("plain markers and section performers", func(t *testing.T) {
+		lines, err := parser.ParsePlainLyrics("[Verse 1: Jay-Z]\nFirst line\n(Beyoncé:) Second line\n[Chorus]\nThird line")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jay-Z", lines[0].Singer)
+		assert.Equal(t, "Beyoncé", lines[1].Singer)
+		assert.Equal(t, "Second line", lines[1].Text)
+		assert.Equal(t, 2, lines[1].WordCount)
+		assert.Equal(t, "", lines[2].Singer)
+	})
+
+	t.Run("webvtt voice spans", func(t *testing.T) {
+		lines, err := parser.ParseWebVTT("WEBVTT\n\n00:01.000 --> 00:02.000\n<v Elton John>Don't go breaking my heart")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Elton John", lines[0].Singer)
+		assert.Equal(t, "Don't go breaking my heart", lines[0].Text)
+	})
+}
+
+func TestStatisticsCalculator_Calculate_Singers(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "One two three", Singer: "M", WordCount: 3},
+		{LineNumber: 2, Text: "Four five", Singer: "F", WordCount: 2},
+		{LineNumber: 3, Text: "Six seven eight", Singer: "M", WordCount: 3},
+		{LineNumber: 4, Text: "Nine ten", WordCount: 2},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, []model.SingerStats{
+		{Singer: "M", Lines: 2, Words: 6, Share: 0.6},
+		{Singer: "F", Lines: 1, Words: 2, Share: 0.2},
+	}, stats.Singers)
+}
+
+func TestStatisticsCalculator_Calculate_NoSingers(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	stats := calc.Calculate([]model.LyricLine{{LineNumber: 1, Text: "Solo", WordCount: 1}})
+
+	assert.Nil(t, stats.Singers)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// StatisticsCalculator computes word and line statistics for lyrics
+type StatisticsCalculator struct {
+	tokenizer Tokenizer // nil picks one by the song's language
+}
+
+// NewStatisticsCalculator creates a new statistics calculator that picks its
+// tokenizer by the song's language, using the script-aware tokenizer when
+// the language is unknown
+func NewStatisticsCalculator() *StatisticsCalculator {
+	return &StatisticsCalculator{}
+}
+
+// NewStatisticsCalculatorWithTokenizer creates a new statistics calculator with the given tokenizer
+func NewStatisticsCalculatorWithTokenizer(tokenizer Tokenizer) *StatisticsCalculator {
+	return &StatisticsCalculator{
+		tokenizer: tokenizer,
+	}
+}
+
+// Calculate computes statistics for the given lyric lines
+func (sc *StatisticsCalculator) Calculate(lines []model.LyricLine) *model.Statistics {
+	return sc.CalculateForLanguage(lines, "")
+}
+
+// CalculateForLanguage computes statistics for lyric lines in the given
+// language, an ISO 639-1 code or empty when unknown. Every word figure is
+// counted with the one tokenizer picked for the song, which the statistics
+// report, rather than with the lines' own word counts.
+func (sc *StatisticsCalculator) CalculateForLanguage(lines []model.LyricLine, language string) *model.Statistics {
+	tokenizer := sc.tokenizer
+	if tokenizer == nil {
+		tokenizer = supportFor(language).tokenizer
+	}
+
+	// Instrumental breaks carry no words and would skew line counts
+	lines = lyricLinesOnly(lines)
+	if len(lines) == 0 {
+		return &model.Statistics{Tokenizer: tokenizer.Name()}
+	}
+
+	uniqueLines := make(map[string]struct{})
+	uniqueWords := make(map[string]struct{})
+	wordsPerLine := make([]int, 0, len(lines))
+	totalWords := 0
+	totalChars := 0
+	longest := 0
+
+	for i, line := range lines {
+		uniqueLines[line.Text] = struct{}{}
+
+		words := normalizeWords(tokenizer, line.Text)
+		for _, word := range words {
+			uniqueWords[word] = struct{}{}
+			totalChars += utf8.RuneCountInString(word)
+		}
+
+		// lines is a filtered copy, so recounting leaves the caller's lines alone
+		lines[i].WordCount = len(words)
+		totalWords += len(words)
+		wordsPerLine = append(wordsPerLine, len(words))
+
+		// Keep the first line when several share the maximum word count
+		if len(words) > lines[longest].WordCount {
+			longest = i
+		}
+	}
+
+	stats := &model.Statistics{
+		TotalLines:         len(lines),
+		UniqueLines:        len(uniqueLines),
+		TotalWords:         totalWords,
+		UniqueWords:        len(uniqueWords),
+		MedianWordsPerLine: median(wordsPerLine),
+		Singers:            singerBreakdown(lines, totalWords),
+		Tokenizer:          tokenizer.Name(),
+		LongestLine: &model.LongestLine{
+			LineNumber: lines[longest].LineNumber,
+			Text:       lines[longest].Text,
+			WordCount:  lines[longest].WordCount,
+		},
+	}
+
+	stats.AverageWordsPerLine = round2(float64(totalWords) / float64(len(lines)))
+	stats.RepetitionRatio = round2(1 - float64(len(uniqueLines))/float64(len(lines)))
+
+	// Characters per word is measured over cleaned words, so punctuation is not counted
+	if totalWords > 0 {
+		stats.AverageCharactersPerWord = round2(float64(totalChars) / float64(totalWords))
+	}
+
+	return stats
+}
+
+// normalizeWords tokenizes text into lowercase words
+func normalizeWords(tokenizer Tokenizer, text string) []string {
+	words := tokenizer.Tokenize(text)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
+
+// median returns the median of the given values
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+
+	return float64(sorted[mid])
+}
+
+// round2 rounds a value to two decimal places
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
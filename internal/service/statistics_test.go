@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatisticsCalculator_Calculate(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "Hello world", WordCount: 2},
+		{LineNumber: 2, Text: "Hello, world!", WordCount: 2},
+		{LineNumber: 3, Text: "This is a longer line", WordCount: 5},
+		{LineNumber: 4, Text: "Hello world", WordCount: 2},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, 4, stats.TotalLines)
+	assert.Equal(t, 3, stats.UniqueLines)
+	assert.Equal(t, 11, stats.TotalWords)
+	// "hello" and "world" are counted once despite punctuation and case
+	assert.Equal(t, 7, stats.UniqueWords)
+	assert.Equal(t, 2.75, stats.AverageWordsPerLine)
+	assert.Equal(t, 0.25, stats.RepetitionRatio)
+	assert.Equal(t, 2.0, stats.MedianWordsPerLine)
+	assert.Equal(t, 4.27, stats.AverageCharactersPerWord)
+
+	assert.NotNil(t, stats.LongestLine)
+	assert.Equal(t, 3, stats.LongestLine.LineNumber)
+	assert.Equal(t, "This is a longer line", stats.LongestLine.Text)
+	assert.Equal(t, 5, stats.LongestLine.WordCount)
+}
+
+func TestStatisticsCalculator_Calculate_EvenMedian(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "One", WordCount: 1},
+		{LineNumber: 2, Text: "Two words", WordCount: 2},
+		{LineNumber: 3, Text: "Now three words", WordCount: 3},
+		{LineNumber: 4, Text: "And now four words", WordCount: 4},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, 2.5, stats.MedianWordsPerLine)
+	assert.Equal(t, 0.0, stats.RepetitionRatio)
+}
+
+func TestStatisticsCalculator_Calculate_LongestLineTieKeepsFirst(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "First long line", WordCount: 3},
+		{LineNumber: 2, Text: "Second long line", WordCount: 3},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, 1, stats.LongestLine.LineNumber)
+}
+
+func TestStatisticsCalculator_Calculate_IgnoresBreaks(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "Hello world", WordCount: 2},
+		{LineNumber: 2, IsBreak: true},
+		{LineNumber: 3, Text: "Goodbye world", WordCount: 2},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, 2, stats.TotalLines)
+	assert.Equal(t, 2, stats.UniqueLines)
+	assert.Equal(t, 2.0, stats.AverageWordsPerLine)
+}
+
+func TestStatisticsCalculator_Calculate_CJK(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "我爱你", WordCount: 3},
+		{LineNumber: 2, Text: "你爱我", WordCount: 3},
+	}
+
+	stats := calc.Calculate(lines)
+
+	assert.Equal(t, 3, stats.UniqueWords)
+	assert.Equal(t, 1.0, stats.AverageCharactersPerWord)
+}
+
+func TestStatisticsCalculator_CalculateForLanguage(t *testing.T) {
+	// Word counts as the parser's script-aware tokenizer sets them
+	lines := []model.LyricLine{
+		{LineNumber: 1, Text: "Sé que ella-ella baila", WordCount: 5},
+		{LineNumber: 2, Text: "well-well-well rock-n-roll", WordCount: 6},
+	}
+
+	tests := []struct {
+		language          string
+		tokenizer         string
+		totalWords        int
+		uniqueWords       int
+		median            float64
+		longestLine       int
+		charactersPerWord float64
+	}{
+		{"", TokenizerUnicode, 11, 8, 5.5, 2, 3.55},   // split at hyphens
+		{"es", TokenizerWhitespace, 6, 6, 3, 1, 7.33}, // hyphenated words are kept whole
+		{"ja", TokenizerUnicode, 11, 8, 5.5, 2, 3.55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			stats := NewStatisticsCalculator().CalculateForLanguage(lines, tt.language)
+
+			assert.Equal(t, tt.tokenizer, stats.Tokenizer)
+			assert.Equal(t, tt.totalWords, stats.TotalWords)
+			assert.Equal(t, tt.uniqueWords, stats.UniqueWords)
+			assert.Equal(t, tt.median, stats.MedianWordsPerLine)
+			assert.Equal(t, tt.longestLine, stats.LongestLine.LineNumber)
+			assert.Equal(t, tt.charactersPerWord, stats.AverageCharactersPerWord)
+		})
+	}
+
+	assert.Equal(t, 5, lines[0].WordCount, "the caller's lines keep their counts")
+}
+
+func TestStatisticsCalculator_Calculate_EmptyLines(t *testing.T) {
+	calc := NewStatisticsCalculator()
+
+	stats := calc.Calculate([]model.LyricLine{})
+
+	assert.NotNil(t, stats)
+	assert.Equal(t, 0, stats.TotalLines)
+	assert.Nil(t, stats.LongestLine)
+}
+
+func TestStatisticsAnalyzer_NilCalculator(t *testing.T) {
+	response := &model.SongAnalysisResponse{}
+
+	err := NewStatisticsAnalyzer(nil).Analyze(context.Background(), &AnalysisInput{Lines: songLines("Hello there")}, response)
+
+	assert.NoError(t, err)
+	assert.Nil(t, response.Statistics)
+}
@@ -0,0 +1,216 @@
+package service
+
+import "strings"
+
+// porterStem reduces an English word to its stem with the Porter (1980)
+// algorithm, so "dancing", "danced" and "dances" all give "danc". Words
+// that are not plain lowercase ASCII are returned unchanged.
+func porterStem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.replaceSuffix(step2Suffixes, 0)
+	s.replaceSuffix(step3Suffixes, 0)
+	s.step4()
+	s.step5()
+
+	return string(s.b)
+}
+
+// Suffix replacements for steps 2 and 3, longest first where suffixes overlap
+var (
+	step2Suffixes = [][2]string{
+		{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+		{"izer", "ize"}, {"abli", "able"}, {"alli", "al"}, {"entli", "ent"},
+		{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+		{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+		{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	}
+	step3Suffixes = [][2]string{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+		{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+	}
+	step4Suffixes = []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+		"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	}
+)
+
+// stemmer holds the word being stemmed
+type stemmer struct {
+	b []byte
+}
+
+// isConsonant reports whether the letter at i is a consonant; y is a
+// consonant at the start of a word or after a vowel
+func (s *stemmer) isConsonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.isConsonant(i-1)
+	default:
+		return true
+	}
+}
+
+// measure counts the vowel-consonant sequences in the first n letters
+func (s *stemmer) measure(n int) int {
+	m := 0
+	i := 0
+	for i < n && s.isConsonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !s.isConsonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		m++
+		for i < n && s.isConsonant(i) {
+			i++
+		}
+	}
+	return m
+}
+
+// hasVowel reports whether the first n letters contain a vowel
+func (s *stemmer) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !s.isConsonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDoubleConsonant reports whether the first n letters end in a double consonant
+func (s *stemmer) endsDoubleConsonant(n int) bool {
+	return n >= 2 && s.b[n-1] == s.b[n-2] && s.isConsonant(n-1)
+}
+
+// endsCVC reports whether the first n letters end consonant-vowel-consonant
+// where the last consonant is not w, x or y, as in "hop"
+func (s *stemmer) endsCVC(n int) bool {
+	if n < 3 || !s.isConsonant(n-1) || s.isConsonant(n-2) || !s.isConsonant(n-3) {
+		return false
+	}
+	last := s.b[n-1]
+	return last != 'w' && last != 'x' && last != 'y'
+}
+
+// hasSuffix reports whether the word ends with suffix
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// setSuffix replaces the last n letters with replacement
+func (s *stemmer) setSuffix(n int, replacement string) {
+	s.b = append(s.b[:len(s.b)-n], replacement...)
+}
+
+func (s *stemmer) step1a() {
+	switch {
+	case s.hasSuffix("sses"):
+		s.setSuffix(4, "ss")
+	case s.hasSuffix("ies"):
+		s.setSuffix(3, "i")
+	case s.hasSuffix("ss"):
+	case s.hasSuffix("s"):
+		s.setSuffix(1, "")
+	}
+}
+
+func (s *stemmer) step1b() {
+	if s.hasSuffix("eed") {
+		if s.measure(len(s.b)-3) > 0 {
+			s.setSuffix(3, "ee")
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if s.hasSuffix(suffix) && s.hasVowel(len(s.b)-len(suffix)) {
+			s.setSuffix(len(suffix), "")
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	n := len(s.b)
+	switch {
+	case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+		s.setSuffix(0, "e")
+	case s.endsDoubleConsonant(n) && s.b[n-1] != 'l' && s.b[n-1] != 's' && s.b[n-1] != 'z':
+		s.setSuffix(1, "")
+	case s.measure(n) == 1 && s.endsCVC(n):
+		s.setSuffix(0, "e")
+	}
+}
+
+func (s *stemmer) step1c() {
+	if s.hasSuffix("y") && s.hasVowel(len(s.b)-1) {
+		s.setSuffix(1, "i")
+	}
+}
+
+// replaceSuffix applies the first matching replacement when the remaining
+// stem has a measure above minMeasure
+func (s *stemmer) replaceSuffix(replacements [][2]string, minMeasure int) {
+	for _, r := range replacements {
+		if !s.hasSuffix(r[0]) {
+			continue
+		}
+		if s.measure(len(s.b)-len(r[0])) > minMeasure {
+			s.setSuffix(len(r[0]), r[1])
+		}
+		return
+	}
+}
+
+func (s *stemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		stem := len(s.b) - len(suffix)
+		if suffix == "ion" && (stem == 0 || (s.b[stem-1] != 's' && s.b[stem-1] != 't')) {
+			continue
+		}
+		if s.measure(stem) > 1 {
+			s.setSuffix(len(suffix), "")
+		}
+		return
+	}
+}
+
+func (s *stemmer) step5() {
+	n := len(s.b)
+	if s.hasSuffix("e") {
+		m := s.measure(n - 1)
+		if m > 1 || (m == 1 && !s.endsCVC(n-1)) {
+			s.setSuffix(1, "")
+		}
+	}
+
+	n = len(s.b)
+	if s.measure(n) > 1 && s.endsDoubleConsonant(n) && s.b[n-1] == 'l' {
+		s.setSuffix(1, "")
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPorterStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"cats", "cat"},
+		{"agreed", "agre"},
+		{"feed", "feed"},
+		{"plastered", "plaster"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"hopping", "hop"},
+		{"falling", "fall"},
+		{"filing", "file"},
+		{"happy", "happi"},
+		{"crying", "cry"},
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"generalization", "gener"},
+		{"hopefulness", "hope"},
+		{"adjustment", "adjust"},
+		{"controlling", "control"},
+		{"dancing", "danc"},
+		{"danced", "danc"},
+		{"dances", "danc"},
+		{"loving", "love"},
+		{"love", "love"},
+		{"is", "is"},
+		{"corazón", "corazón"},
+		{"Dancing", "Dancing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.want, porterStem(tt.word))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Default input limits applied by NewParser
+const (
+	DefaultMaxLineLength = 4096
+	DefaultMaxLines      = 10000
+	DefaultMaxBytes      = 1 << 20
+)
+
+// Errors returned when lyrics input exceeds the parser's limits
+var (
+	ErrLineTooLong   = errors.New("lyrics line too long")
+	ErrTooManyLines  = errors.New("too many lyrics lines")
+	ErrInputTooLarge = errors.New("lyrics input too large")
+)
+
+// ParseLimits bounds the input the parser accepts. A zero value disables that limit.
+type ParseLimits struct {
+	// MaxLineLength is the maximum length of a single line in bytes
+	MaxLineLength int
+
+	// MaxLines is the maximum number of source lines, including blank ones
+	MaxLines int
+
+	// MaxBytes is the maximum total input size in bytes
+	MaxBytes int64
+}
+
+// DefaultParseLimits returns limits that comfortably fit any real song
+func DefaultParseLimits() ParseLimits {
+	return ParseLimits{
+		MaxLineLength: DefaultMaxLineLength,
+		MaxLines:      DefaultMaxLines,
+		MaxBytes:      DefaultMaxBytes,
+	}
+}
+
+// lineScanner reads input line by line, enforcing parse limits and stopping
+// as soon as the context is cancelled
+type lineScanner struct {
+	ctx        context.Context
+	scanner    *bufio.Scanner
+	counter    *countingReader
+	limits     ParseLimits
+	lineNumber int
+	line       string
+	err        error
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	r     io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.count += int64(n)
+	return n, err
+}
+
+// newLineScanner creates a scanner over r using the parser's limits
+func (p *Parser) newLineScanner(ctx context.Context, r io.Reader) *lineScanner {
+	limits := p.limits
+
+	// Never read more than one byte past the limit, so oversized input is
+	// rejected without buffering all of it
+	if limits.MaxBytes > 0 {
+		r = io.LimitReader(r, limits.MaxBytes+1)
+	}
+	counter := &countingReader{r: r}
+
+	scanner := bufio.NewScanner(counter)
+	if limits.MaxLineLength > 0 {
+		// Leave room for the \r\n terminator
+		size := limits.MaxLineLength + 2
+		scanner.Buffer(make([]byte, 0, min(size, bufio.MaxScanTokenSize)), size)
+	}
+
+	return &lineScanner{
+		ctx:     ctx,
+		scanner: scanner,
+		counter: counter,
+		limits:  limits,
+	}
+}
+
+// Scan advances to the next line. It returns false at the end of the input
+// or on error; Err reports which.
+func (s *lineScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+			s.err = fmt.Errorf("%w: line %d exceeds %d bytes", ErrLineTooLong, s.lineNumber+1, s.limits.MaxLineLength)
+		} else if err != nil {
+			s.err = err
+		}
+		s.checkSize()
+		return false
+	}
+
+	s.lineNumber++
+	s.line = strings.TrimSuffix(s.scanner.Text(), "\r")
+
+	if s.checkSize() {
+		return false
+	}
+
+	if s.limits.MaxLines > 0 && s.lineNumber > s.limits.MaxLines {
+		s.err = fmt.Errorf("%w: more than %d lines", ErrTooManyLines, s.limits.MaxLines)
+		return false
+	}
+
+	if s.limits.MaxLineLength > 0 && len(s.line) > s.limits.MaxLineLength {
+		s.err = fmt.Errorf("%w: line %d exceeds %d bytes", ErrLineTooLong, s.lineNumber, s.limits.MaxLineLength)
+		return false
+	}
+
+	return true
+}
+
+// checkSize records ErrInputTooLarge once more than MaxBytes have been read
+func (s *lineScanner) checkSize() bool {
+	if s.limits.MaxBytes > 0 && s.counter.count > s.limits.MaxBytes {
+		s.err = fmt.Errorf("%w: exceeds %d bytes", ErrInputTooLarge, s.limits.MaxBytes)
+		return true
+	}
+	return false
+}
+
+// Text returns the current line without its line terminator
+func (s *lineScanner) Text() string {
+	return s.line
+}
+
+// LineNumber returns the 1-based source line number of the current line
+func (s *lineScanner) LineNumber() int {
+	return s.lineNumber
+}
+
+// BytesRead returns the number of input bytes consumed so far
+func (s *lineScanner) BytesRead() int64 {
+	return s.counter.count
+}
+
+// Err returns the first error encountered, if any
+func (s *lineScanner) Err() error {
+	return s.err
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_StreamSyncedLyrics(t *testing.T) {
+	parser := NewParser()
+	input := "[ti:Song]\n[00:05.00] First\nnot timed\n[00:10.00][00:20.00] Repeat\n"
+
+	var texts []string
+	var numbers []int
+	diagnostics, err := parser.StreamSyncedLyrics(context.Background(), strings.NewReader(input), func(line model.LyricLine) error {
+		texts = append(texts, line.Text)
+		numbers = append(numbers, line.LineNumber)
+		assert.Nil(t, line.EndMs)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First", "Repeat", "Repeat"}, texts)
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, 3, diagnostics[0].LineNumber)
+	assert.Equal(t, model.DiagnosticMissingTimestamp, diagnostics[0].Reason)
+}
+
+func TestParser_StreamSyncedLyrics_StopsOnYieldError(t *testing.T) {
+	parser := NewParser()
+	stop := errors.New("stop")
+	count := 0
+
+	_, err := parser.StreamSyncedLyrics(context.Background(), strings.NewReader("[00:01.00] One\n[00:02.00] Two\n[00:03.00] Three"), func(model.LyricLine) error {
+		count++
+		if count == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 2, count)
+}
+
+func TestParser_StreamPlainLyrics(t *testing.T) {
+	parser := NewParser()
+
+	var texts []string
+	sections, err := parser.StreamPlainLyrics(context.Background(), strings.NewReader("[Verse]\nHello there\n\n[Chorus]\nSing along\r\n"), func(line model.LyricLine) error {
+		texts = append(texts, line.Text)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello there", "Sing along"}, texts)
+	require.Len(t, sections, 2)
+	assert.Equal(t, model.SectionChorus, sections[1].Type)
+	assert.Equal(t, 2, sections[1].StartLine)
+}
+
+func TestParser_ReaderContextCancelled(t *testing.T) {
+	parser := NewParser()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := parser.ParseSyncedLyricsReader(ctx, strings.NewReader("[00:01.00] One"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, _, err = parser.ParsePlainLyricsReader(ctx, strings.NewReader("One"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, _, _, err = parser.ParseTimedLyricsReader(ctx, strings.NewReader("1\n00:00:01,000 --> 00:00:02,000\nOne"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, _, _, err = parser.ParseTimedLyricsReader(ctx, strings.NewReader("WEBVTT\n\n00:01.000 --> 00:02.000\nOne"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParser_ParseTimedLyricsReader_Limits(t *testing.T) {
+	cue := "00:00:01,000 --> 00:00:02,000\nOne\n\n"
+
+	tests := []struct {
+		name     string
+		limits   ParseLimits
+		input    string
+		format   string
+		expected error
+	}{
+		{
+			name:   "srt within limits",
+			limits: ParseLimits{MaxLines: 9},
+			input:  strings.Repeat(cue, 3),
+			format: model.FormatSRT,
+		},
+		{
+			name:     "srt with too many lines",
+			limits:   ParseLimits{MaxLines: 5},
+			input:    strings.Repeat(cue, 3),
+			format:   model.FormatSRT,
+			expected: ErrTooManyLines,
+		},
+		{
+			name:     "webvtt too large",
+			limits:   ParseLimits{MaxBytes: 100},
+			input:    "WEBVTT\n\n" + strings.Repeat("00:01.000 --> 00:02.000\nOne\n\n", 20),
+			format:   model.FormatWebVTT,
+			expected: ErrInputTooLarge,
+		},
+		{
+			name:     "oversized input is detected from its start",
+			limits:   ParseLimits{MaxBytes: 1000},
+			input:    strings.Repeat("[00:01.00] la la la\n", 1000) + cue,
+			format:   model.FormatLRC,
+			expected: ErrInputTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParserWithConfig(ParserConfig{Limits: tt.limits})
+
+			_, format, _, err := parser.ParseTimedLyricsReader(context.Background(), strings.NewReader(tt.input))
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.format, format)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParser_ParseLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   ParseLimits
+		input    string
+		expected error
+	}{
+		{
+			name:   "within limits",
+			limits: ParseLimits{MaxLineLength: 20, MaxLines: 2, MaxBytes: 40},
+			input:  "[00:01.00] One\n[00:02.00] Two",
+		},
+		{
+			name:     "line too long",
+			limits:   ParseLimits{MaxLineLength: 10},
+			input:    "[00:01.00] One\n[00:02.00] Two",
+			expected: ErrLineTooLong,
+		},
+		{
+			name:     "line longer than scanner buffer",
+			limits:   ParseLimits{MaxLineLength: 10},
+			input:    "[00:01.00] " + strings.Repeat("la ", 100),
+			expected: ErrLineTooLong,
+		},
+		{
+			name:     "too many lines",
+			limits:   ParseLimits{MaxLines: 2},
+			input:    "[00:01.00] One\n\n[00:02.00] Two",
+			expected: ErrTooManyLines,
+		},
+		{
+			name:     "input too large",
+			limits:   ParseLimits{MaxBytes: 20},
+			input:    "[00:01.00] One\n[00:02.00] Two",
+			expected: ErrInputTooLarge,
+		},
+		{
+			name:   "zero limits are unlimited",
+			limits: ParseLimits{},
+			input:  strings.Repeat("[00:01.00] "+strings.Repeat("la ", 2000)+"\n", 20),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParserWithConfig(ParserConfig{Limits: tt.limits})
+
+			_, _, err := parser.ParseSyncedLyricsWithDiagnostics(tt.input)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+
+			_, _, err = parser.ParsePlainLyricsWithSections(tt.input)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
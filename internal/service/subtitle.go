@@ -0,0 +1,237 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// formatDetectBytes is how much of the input format detection looks at.
+// Cue timings and the WEBVTT header appear within the first few lines, so
+// detection never has to read past the parser's input limits.
+const formatDetectBytes = 4096
+
+// cueBlock is a group of consecutive non-blank subtitle lines
+type cueBlock struct {
+	sourceLine int
+	lines      []string
+}
+
+// ParseSRT parses SubRip (.srt) subtitles into timed lyric lines
+func (p *Parser) ParseSRT(srt string) ([]model.LyricLine, error) {
+	lines, _, err := p.parseCues(context.Background(), strings.NewReader(srt), model.FormatSRT)
+	return lines, err
+}
+
+// ParseWebVTT parses WebVTT (.vtt) subtitles into timed lyric lines
+func (p *Parser) ParseWebVTT(vtt string) ([]model.LyricLine, error) {
+	lines, _, err := p.parseCues(context.Background(), strings.NewReader(vtt), model.FormatWebVTT)
+	return lines, err
+}
+
+// DetectFormat guesses the format of timed lyrics: WebVTT, SRT or LRC
+func (p *Parser) DetectFormat(input string) string {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(input), "\ufeff")
+
+	switch {
+	case strings.HasPrefix(trimmed, "WEBVTT"):
+		return model.FormatWebVTT
+	case p.srtTimingRegex.MatchString(trimmed):
+		return model.FormatSRT
+	case p.cueTimingRegex.MatchString(trimmed):
+		// Cue timings without the WEBVTT header still use WebVTT syntax
+		return model.FormatWebVTT
+	default:
+		return model.FormatLRC
+	}
+}
+
+// ParseTimedLyrics detects the format of timed lyrics and parses them,
+// returning the lines, the detected format and any parser diagnostics
+func (p *Parser) ParseTimedLyrics(input string) ([]model.LyricLine, string, []model.Diagnostic, error) {
+	return p.ParseTimedLyricsReader(context.Background(), strings.NewReader(input))
+}
+
+// ParseTimedLyricsReader detects the format of timed lyrics from the start
+// of r and parses them within the parser's input limits, stopping as soon
+// as ctx is cancelled
+func (p *Parser) ParseTimedLyricsReader(ctx context.Context, r io.Reader) ([]model.LyricLine, string, []model.Diagnostic, error) {
+	buffered := bufio.NewReaderSize(r, formatDetectBytes)
+	head, err := buffered.Peek(formatDetectBytes)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", nil, err
+	}
+	format := p.DetectFormat(string(head))
+
+	var lines []model.LyricLine
+	var diagnostics []model.Diagnostic
+
+	if format == model.FormatLRC {
+		lines, diagnostics, err = p.ParseSyncedLyricsReader(ctx, buffered)
+	} else {
+		lines, diagnostics, err = p.parseCues(ctx, buffered, format)
+	}
+
+	if err != nil {
+		return nil, format, nil, err
+	}
+
+	return lines, format, diagnostics, nil
+}
+
+// parseCues parses SRT or WebVTT cues. Both formats are blocks separated by
+// blank lines with an optional identifier, a timing line and the cue text.
+func (p *Parser) parseCues(ctx context.Context, r io.Reader, format string) ([]model.LyricLine, []model.Diagnostic, error) {
+	blocks, err := p.splitCueBlocks(ctx, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, nil, fmt.Errorf("%s lyrics are empty", format)
+	}
+
+	diagnostics := &diagnosticsCollector{}
+	var timedLines []timedLine
+
+	for i, block := range blocks {
+		first := block.lines[0]
+
+		// WebVTT header and metadata blocks carry no cues
+		if format == model.FormatWebVTT {
+			if i == 0 && strings.HasPrefix(first, "WEBVTT") {
+				continue
+			}
+			if strings.HasPrefix(first, "NOTE") || first == "STYLE" || first == "REGION" {
+				continue
+			}
+		}
+
+		// The timing line is first, or second after a cue identifier
+		timingIndex := 0
+		if !strings.Contains(first, "-->") && len(block.lines) > 1 {
+			timingIndex = 1
+		}
+
+		matches := p.cueTimingRegex.FindStringSubmatch(block.lines[timingIndex])
+		if len(matches) != 3 {
+			diagnostics.add(block.sourceLine, strings.Join(block.lines, " "), model.DiagnosticMalformedCue)
+			continue
+		}
+
+		timingLine := block.sourceLine + timingIndex
+		startMs, startErr := parseCueTimestamp(matches[1])
+		endMs, endErr := parseCueTimestamp(matches[2])
+		if startErr != nil || endErr != nil || endMs < startMs {
+			diagnostics.add(timingLine, block.lines[timingIndex], model.DiagnosticInvalidTimestamp)
+			continue
+		}
+
+		// Multi-line cues become a single lyric line; an empty cue marks a break
+		cueLines := block.lines[timingIndex+1:]
+		singer := ""
+		if voice := p.vttVoiceRegex.FindStringSubmatch(strings.Join(cueLines, " ")); voice != nil {
+			singer = strings.TrimSpace(voice[1])
+		}
+
+		text := p.cleanCueText(cueLines)
+		if singer == "" {
+			singer, text = p.extractSinger(text)
+		}
+		timestamp := formatTimestamp(startMs)
+
+		timedLines = append(timedLines, timedLine{
+			startMs:    startMs,
+			sourceLine: timingLine,
+			raw:        block.lines[timingIndex],
+			line: model.LyricLine{
+				Timestamp:  &timestamp,
+				StartMs:    intPtr(startMs),
+				EndMs:      intPtr(endMs),
+				DurationMs: intPtr(endMs - startMs),
+				Text:       text,
+				Singer:     singer,
+				WordCount:  p.countWords(text),
+				IsBreak:    text == "",
+			},
+		})
+	}
+
+	checkTimestampOrder(timedLines, false, diagnostics)
+
+	var lyricLines []model.LyricLine
+	for i, tl := range timedLines {
+		tl.line.LineNumber = i + 1
+		lyricLines = append(lyricLines, tl.line)
+	}
+
+	return lyricLines, diagnostics.list(), nil
+}
+
+// cleanCueText strips subtitle markup (<i>, <v Singer>, {\an8}) and entities
+func (p *Parser) cleanCueText(lines []string) string {
+	text := strings.Join(lines, " ")
+	text = p.cueMarkupRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// splitCueBlocks groups input lines into blocks separated by blank lines,
+// within the parser's input limits
+func (p *Parser) splitCueBlocks(ctx context.Context, r io.Reader) ([]cueBlock, error) {
+	scanner := p.newLineScanner(ctx, r)
+	var blocks []cueBlock
+	var current *cueBlock
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" {
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			blocks = append(blocks, cueBlock{sourceLine: scanner.LineNumber()})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+
+	return blocks, scanner.Err()
+}
+
+// parseCueTimestamp converts an SRT or WebVTT timestamp ([hh:]mm:ss,mmm or
+// [hh:]mm:ss.mmm) to milliseconds
+func parseCueTimestamp(timestamp string) (int, error) {
+	parts := strings.Split(strings.Replace(timestamp, ",", ".", 1), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid cue timestamp: %s", timestamp)
+	}
+
+	hours := 0
+	if len(parts) == 3 {
+		h, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid hours: %w", err)
+		}
+		hours = h
+		parts = parts[1:]
+	}
+
+	minutes, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes: %w", err)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid seconds: %w", err)
+	}
+
+	return toMilliseconds(float64(hours*3600+minutes*60) + seconds), nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseSRT(t *testing.T) {
+	input := "1\r\n00:00:12,000 --> 00:00:15,500\r\n<i>Never gonna</i>\r\ngive you up\r\n\r\n" +
+		"2\r\n00:00:15,500 --> 00:00:18,250\r\n{\\an8}Never gonna let you down\r\n"
+
+	parser := NewParser()
+	lines, err := parser.ParseSRT(input)
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 2)
+
+	assert.Equal(t, 1, lines[0].LineNumber)
+	assert.Equal(t, "Never gonna give you up", lines[0].Text)
+	assert.Equal(t, 5, lines[0].WordCount)
+	assert.Equal(t, "00:12.00", *lines[0].Timestamp)
+	assert.Equal(t, 12000, *lines[0].StartMs)
+	assert.Equal(t, 15500, *lines[0].EndMs)
+	assert.Equal(t, 3500, *lines[0].DurationMs)
+
+	assert.Equal(t, "Never gonna let you down", lines[1].Text)
+	assert.Equal(t, 18250, *lines[1].EndMs)
+}
+
+func TestParser_ParseWebVTT(t *testing.T) {
+	input := `WEBVTT - lyrics
+
+NOTE generated by hand
+
+STYLE
+::cue { color: white }
+
+intro
+00:05.000 --> 00:10.000 align:start
+
+00:00:10.000 --> 00:00:14.000
+<v Singer>Hello &amp; welcome</v>
+
+01:00:00.000 --> 01:00:02.500
+Late line`
+
+	parser := NewParser()
+	lines, err := parser.ParseWebVTT(input)
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 3)
+
+	// A cue without text is an instrumental break
+	assert.True(t, lines[0].IsBreak)
+	assert.Equal(t, 5000, *lines[0].StartMs)
+
+	assert.Equal(t, "Hello & welcome", lines[1].Text)
+	assert.Equal(t, 10000, *lines[1].StartMs)
+	assert.Equal(t, 14000, *lines[1].EndMs)
+
+	assert.Equal(t, 3600000, *lines[2].StartMs)
+	assert.Equal(t, "60:00.00", *lines[2].Timestamp)
+}
+
+func TestParser_ParseSRT_Empty(t *testing.T) {
+	parser := NewParser()
+
+	_, err := parser.ParseSRT("  \n ")
+
+	assert.Error(t, err)
+}
+
+func TestParser_DetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"webvtt header", "WEBVTT\n\n00:01.000 --> 00:02.000\nHi", model.FormatWebVTT},
+		{"webvtt with BOM", "\ufeffWEBVTT\n", model.FormatWebVTT},
+		{"srt", "1\n00:00:01,000 --> 00:00:02,000\nHi", model.FormatSRT},
+		{"headerless vtt cues", "00:00:01.000 --> 00:00:02.000\nHi", model.FormatWebVTT},
+		{"lrc", "[00:01.00] Hi", model.FormatLRC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			assert.Equal(t, tt.expected, parser.DetectFormat(tt.input))
+		})
+	}
+}
+
+func TestParser_ParseTimedLyrics_Diagnostics(t *testing.T) {
+	input := "1\n00:00:05,000 --> 00:00:06,000\nFirst\n\n2\nnot a timing line\nLost\n\n3\n00:00:02,000 --> 00:00:03,000\nEarlier"
+
+	parser := NewParser()
+	lines, format, diagnostics, err := parser.ParseTimedLyrics(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.FormatSRT, format)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []model.Diagnostic{
+		{LineNumber: 5, Raw: "2 not a timing line Lost", Reason: model.DiagnosticMalformedCue},
+		{LineNumber: 10, Raw: "00:00:02,000 --> 00:00:03,000", Reason: model.DiagnosticOutOfOrder},
+	}, diagnostics)
+}
+
+func TestParseCueTimestamp(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		expected  int
+		wantError bool
+	}{
+		{"00:01:02,500", 62500, false},
+		{"01:02.500", 62500, false},
+		{"1:00:00.000", 3600000, false},
+		{"abc", 0, true},
+		{"00:xx.000", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timestamp, func(t *testing.T) {
+			result, err := parseCueTimestamp(tt.timestamp)
+
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
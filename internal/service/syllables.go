@@ -0,0 +1,83 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SyllableCounter counts syllables with the bundled pronunciation dictionary,
+// falling back to spelling rules for words it does not know
+type SyllableCounter struct {
+	dictionary *pronunciationDictionary
+}
+
+// NewSyllableCounter creates a syllable counter using the bundled dictionary
+func NewSyllableCounter() *SyllableCounter {
+	return &SyllableCounter{
+		dictionary: defaultPronunciations(),
+	}
+}
+
+// Count returns the number of syllables in a single word
+func (sc *SyllableCounter) Count(word string) int {
+	return sc.CountInLanguage(word, "")
+}
+
+// CountInLanguage returns the number of syllables in a single word of the
+// given language. The dictionary is English, so words in other languages
+// are always counted by spelling rules.
+func (sc *SyllableCounter) CountInLanguage(word, language string) int {
+	word = strings.ToLower(strings.Trim(word, "'’"))
+	if word == "" {
+		return 0
+	}
+
+	if outsideEnglishData(language) {
+		return ruleSyllables(word)
+	}
+
+	if phonemes, ok := sc.dictionary.lookup(word); ok {
+		return syllableCount(phonemes)
+	}
+
+	return ruleSyllables(word)
+}
+
+// ruleSyllables estimates syllables from spelling. Latin and Cyrillic words
+// count vowel groups, less a silent final "e"; Han, kana and Hangul count
+// one syllable per character, as the tokenizer already splits them.
+func ruleSyllables(word string) int {
+	runes := []rune(word)
+
+	if isSyllabicScript(runes[0]) {
+		return len(runes)
+	}
+
+	count := 0
+	previousVowel := false
+	for _, r := range runes {
+		vowel := isSyllableVowel(r)
+		if vowel && !previousVowel {
+			count++
+		}
+		previousVowel = vowel
+	}
+
+	// A final "e" is usually silent ("time"), but not in "-le" after a consonant ("little")
+	n := len(runes)
+	if count > 1 && runes[n-1] == 'e' && !isSyllableVowel(runes[n-2]) && !(runes[n-2] == 'l' && n > 2 && !isSyllableVowel(runes[n-3])) {
+		count--
+	}
+
+	return max(1, count)
+}
+
+// isSyllableVowel reports whether r starts a syllable nucleus in Latin or Cyrillic spelling
+func isSyllableVowel(r rune) bool {
+	return isSpellingVowel(r) || r == 'y' || strings.ContainsRune("аеёиоуыэюя", r)
+}
+
+// isSyllabicScript reports whether each character of r's script is about one syllable
+func isSyllabicScript(r rune) bool {
+	return isIdeographic(r) || unicode.Is(unicode.Hangul, r)
+}
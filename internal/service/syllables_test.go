@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyllableCounter_Count(t *testing.T) {
+	counter := NewSyllableCounter()
+
+	tests := []struct {
+		word     string
+		expected int
+	}{
+		// From the dictionary
+		{"beautiful", 3},
+		{"fire", 2},
+		{"everything", 3},
+		{"Tonight", 2},
+		// Spelling rules
+		{"shine", 1},
+		{"candle", 2},
+		{"rhythm", 1},
+		{"amazing", 3},
+		{"corazón", 3},
+		{"привет", 2},
+		{"사랑해", 3},
+		{"愛", 1},
+		{"'bout", 1},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.expected, counter.Count(tt.word))
+		})
+	}
+}
+
+func TestSyllableCounter_CountInLanguage(t *testing.T) {
+	counter := NewSyllableCounter()
+
+	assert.Equal(t, 2, counter.CountInLanguage("fire", "en"))
+	assert.Equal(t, 2, counter.CountInLanguage("fire", ""), "unknown languages use the dictionary")
+	assert.Equal(t, 1, counter.CountInLanguage("fire", "es"), "other languages are counted by spelling")
+}
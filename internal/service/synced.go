@@ -1,58 +1,258 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
 )
 
+// timedLine is a parsed synced line before line numbers are assigned
+type timedLine struct {
+	startMs    int
+	sourceLine int
+	raw        string
+	line       model.LyricLine
+}
+
 // ParseSyncedLyrics parses synced lyrics with timestamps into structured format
 func (p *Parser) ParseSyncedLyrics(syncedLyrics string) ([]model.LyricLine, error) {
-	if syncedLyrics == "" {
-		return nil, fmt.Errorf("synced lyrics are empty")
+	lines, _, err := p.ParseSyncedLyricsWithDiagnostics(syncedLyrics)
+	return lines, err
+}
+
+// ParseSyncedLyricsWithDiagnostics parses synced lyrics and reports every
+// skipped or suspicious source line instead of dropping it silently
+func (p *Parser) ParseSyncedLyricsWithDiagnostics(syncedLyrics string) ([]model.LyricLine, []model.Diagnostic, error) {
+	return p.ParseSyncedLyricsReader(context.Background(), strings.NewReader(syncedLyrics))
+}
+
+// ParseSyncedLyricsReader parses synced lyrics from r, restoring the song's
+// order for compressed LRC and filling in line end times
+func (p *Parser) ParseSyncedLyricsReader(ctx context.Context, r io.Reader) ([]model.LyricLine, []model.Diagnostic, error) {
+	var timedLines []timedLine
+	diagnostics := &diagnosticsCollector{}
+
+	compressed, err := p.scanSynced(ctx, r, diagnostics, func(tl timedLine) error {
+		timedLines = append(timedLines, tl)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkTimestampOrder(timedLines, compressed, diagnostics)
+
+	// Compressed LRC lists repeats out of order, so restore the song's real sequence
+	if compressed {
+		sort.SliceStable(timedLines, func(i, j int) bool {
+			return timedLines[i].startMs < timedLines[j].startMs
+		})
 	}
 
-	lines := strings.Split(syncedLyrics, "\n")
 	var lyricLines []model.LyricLine
-	lineNumber := 1
+	for i, tl := range timedLines {
+		tl.line.LineNumber = i + 1
+		lyricLines = append(lyricLines, tl.line)
+	}
+
+	// Track duration is unknown here, so the last line is left open-ended
+	assignEndTimes(lyricLines, 0)
+
+	return lyricLines, diagnostics.list(), nil
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+// StreamSyncedLyrics parses synced lyrics from r and passes each line to
+// yield as soon as it is read, without buffering the whole song. Lines are
+// numbered in source order and carry no end times, since those depend on
+// the next line. Parsing stops at the first error returned by yield.
+func (p *Parser) StreamSyncedLyrics(ctx context.Context, r io.Reader, yield func(model.LyricLine) error) ([]model.Diagnostic, error) {
+	diagnostics := &diagnosticsCollector{}
+	lineNumber := 0
+
+	_, err := p.scanSynced(ctx, r, diagnostics, func(tl timedLine) error {
+		lineNumber++
+		tl.line.LineNumber = lineNumber
+		return yield(tl.line)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return diagnostics.list(), nil
+}
+
+// scanSynced reads LRC lines from r and passes each timed line to yield in
+// source order. It reports whether the input uses compressed multi-tag lines.
+func (p *Parser) scanSynced(ctx context.Context, r io.Reader, diagnostics *diagnosticsCollector, yield func(timedLine) error) (bool, error) {
+	scanner := p.newLineScanner(ctx, r)
+	header := &model.LRCHeader{}
+	compressed := false
+
+	for scanner.Scan() {
+		sourceLine := scanner.LineNumber()
+		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
 			continue
 		}
 
-		// Match timestamp pattern [mm:ss.xx] text
+		// Header [offset:] shifts every following line and word timestamp;
+		// ID tags come before the lyrics in practice
+		if p.applyHeaderTag(header, line) {
+			continue
+		}
+
+		// Match timestamp pattern [mm:ss.xx] text, or [mm:ss.xx][mm:ss.xx]... text
 		matches := p.timestampRegex.FindStringSubmatch(line)
-		if len(matches) != 4 {
-			// Skip lines that don't match pattern
+		if len(matches) != 3 {
+			// Skip lines that don't match pattern, reporting all but known header tags
+			if reason := p.classifyUntimedLine(line); reason != "" {
+				diagnostics.add(sourceLine, line, reason)
+			}
 			continue
 		}
 
-		minutes := matches[1]
-		seconds := matches[2]
-		text := strings.TrimSpace(matches[3])
+		tags := p.timeTagRegex.FindAllStringSubmatch(matches[1], -1)
+		if len(tags) > 1 {
+			compressed = true
+		}
+
+		var timestamps []string
+		var startTimes []int
+		for _, tag := range tags {
+			minutes := tag[1]
+			seconds := tag[2]
+
+			// Parse timestamp
+			timestamp := fmt.Sprintf("%s:%s", minutes, seconds)
+			startSeconds, err := p.ParseTimestamp(timestamp)
+			if err != nil {
+				diagnostics.add(sourceLine, line, model.DiagnosticInvalidTimestamp)
+				continue
+			}
+
+			// Kept for compatibility, but [01:75.00] is almost certainly a typo
+			if secondsOutOfRange(seconds) {
+				diagnostics.add(sourceLine, line, model.DiagnosticSecondsOutOfRange)
+			}
+
+			timestamps = append(timestamps, timestamp)
+			startTimes = append(startTimes, toMilliseconds(startSeconds))
+		}
 
-		// Parse timestamp
-		timestamp := fmt.Sprintf("%s:%s", minutes, seconds)
-		_, err := p.ParseTimestamp(timestamp)
-		if err != nil {
+		if len(startTimes) == 0 {
 			continue
 		}
 
+		// Voice markers (v1:, v2:) precede any word tags
+		singer, rawText := p.extractSinger(strings.TrimSpace(matches[2]))
+
+		// Extract enhanced LRC word tags so they don't leak into the text
+		// A timed line with no text marks an instrumental break
+		text, words := p.parseWordTimestamps(rawText, startTimes[0])
+		isBreak := text == ""
+
 		// Count words
-		wordCount := len(strings.Fields(text))
+		wordCount := p.countWords(text)
 
-		lyricLines = append(lyricLines, model.LyricLine{
-			LineNumber: lineNumber,
-			Timestamp:  &timestamp,
-			Text:       text,
-			WordCount:  wordCount,
-		})
+		// Expand one line per timestamp; word tags are timed against the first one
+		offsetMs := header.OffsetMs
+		for i, timestamp := range timestamps {
+			startMs := startTimes[i]
+			lineWords := shiftWords(words, startMs-startTimes[0]-offsetMs)
 
-		lineNumber++
+			if offsetMs != 0 {
+				startMs = applyOffset(startMs, offsetMs)
+				timestamp = formatTimestamp(startMs)
+			}
+
+			err := yield(timedLine{
+				startMs:    startMs,
+				sourceLine: sourceLine,
+				raw:        line,
+				line: model.LyricLine{
+					Timestamp: &timestamp,
+					StartMs:   intPtr(startMs),
+					Text:      text,
+					Singer:    singer,
+					WordCount: wordCount,
+					Words:     lineWords,
+					IsBreak:   isBreak,
+				},
+			})
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	if scanner.BytesRead() == 0 {
+		return false, fmt.Errorf("synced lyrics are empty")
+	}
+
+	return compressed, nil
+}
+
+// classifyUntimedLine returns the diagnostic reason for a line without a
+// valid timestamp, or "" for recognised header tags that are expected
+func (p *Parser) classifyUntimedLine(line string) string {
+	switch {
+	case p.headerRegex.MatchString(line):
+		return ""
+	case p.idTagRegex.MatchString(line):
+		return model.DiagnosticUnsupportedTag
+	case strings.HasPrefix(line, "["):
+		return model.DiagnosticMalformedTag
+	default:
+		return model.DiagnosticMissingTimestamp
+	}
+}
+
+// checkTimestampOrder reports duplicate timestamps and, for files that are
+// not compressed LRC, lines whose timestamp goes backwards
+func checkTimestampOrder(timedLines []timedLine, compressed bool, diagnostics *diagnosticsCollector) {
+	seen := make(map[int]bool)
+
+	for i, tl := range timedLines {
+		if seen[tl.startMs] {
+			diagnostics.add(tl.sourceLine, tl.raw, model.DiagnosticDuplicateTimestamp)
+		}
+		seen[tl.startMs] = true
+
+		if !compressed && i > 0 && tl.startMs < timedLines[i-1].startMs {
+			diagnostics.add(tl.sourceLine, tl.raw, model.DiagnosticOutOfOrder)
+		}
+	}
+}
+
+// secondsOutOfRange reports whether the seconds part of a timestamp is 60 or more
+func secondsOutOfRange(seconds string) bool {
+	value, err := strconv.ParseFloat(seconds, 64)
+	return err == nil && value >= 60
+}
+
+// shiftWords returns a copy of words moved by deltaMs, clamped at zero
+func shiftWords(words []model.Word, deltaMs int) []model.Word {
+	if words == nil {
+		return nil
+	}
+
+	shifted := make([]model.Word, len(words))
+	for i, word := range words {
+		shifted[i] = word
+		shifted[i].StartMs = max(0, word.StartMs+deltaMs)
+		if word.EndMs > 0 {
+			shifted[i].EndMs = max(0, word.EndMs+deltaMs)
+		}
 	}
 
-	return lyricLines, nil
+	return shifted
 }
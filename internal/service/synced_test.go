@@ -66,16 +66,21 @@ func TestParser_ParseSyncedLyrics(t *testing.T) {
 			},
 		},
 		{
-			name: "empty text with timestamp - skipped",
+			name: "empty text with timestamp - kept as break",
 			input: `
 				[00:10.00]
 				[00:15.00] Valid line
 			`,
 			shouldError:   false,
-			expectedLines: 1,
+			expectedLines: 2,
 			validate: func(t *testing.T, lines []model.LyricLine) {
-				// Empty text lines should be skipped by regex
-				assert.Equal(t, "Valid line", lines[0].Text)
+				// Timed blank lines mark instrumental breaks
+				assert.True(t, lines[0].IsBreak)
+				assert.Equal(t, "", lines[0].Text)
+				assert.Equal(t, 0, lines[0].WordCount)
+				assert.Equal(t, 15000, *lines[0].EndMs)
+				assert.False(t, lines[1].IsBreak)
+				assert.Equal(t, "Valid line", lines[1].Text)
 			},
 		},
 		{
@@ -211,6 +216,61 @@ func TestParser_ParseSyncedLyrics(t *testing.T) {
 				assert.Equal(t, "00:20.99", *lines[2].Timestamp)
 			},
 		},
+		{
+			name: "compressed multi-timestamp lines expanded in time order",
+			input: `
+				[00:12.00][01:05.30][02:10.00]Chorus line
+				[00:20.00]Verse one
+				[01:15.00]Verse two
+			`,
+			shouldError:   false,
+			expectedLines: 5,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				expected := []struct {
+					timestamp string
+					text      string
+				}{
+					{"00:12.00", "Chorus line"},
+					{"00:20.00", "Verse one"},
+					{"01:05.30", "Chorus line"},
+					{"01:15.00", "Verse two"},
+					{"02:10.00", "Chorus line"},
+				}
+				for i, e := range expected {
+					assert.Equal(t, i+1, lines[i].LineNumber)
+					assert.Equal(t, e.timestamp, *lines[i].Timestamp)
+					assert.Equal(t, e.text, lines[i].Text)
+					assert.Equal(t, 2, lines[i].WordCount)
+				}
+			},
+		},
+		{
+			name: "compressed line with only tags expanded into breaks",
+			input: `
+				[00:10.00][00:20.00]
+				[00:15.00] Valid line
+			`,
+			shouldError:   false,
+			expectedLines: 3,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.True(t, lines[0].IsBreak)
+				assert.Equal(t, "Valid line", lines[1].Text)
+				assert.True(t, lines[2].IsBreak)
+			},
+		},
+		{
+			name: "compressed line with word tags shifts words per repeat",
+			input: `
+				[00:10.00][00:30.00]<00:10.00>Hey <00:10.50>you<00:11.00>
+			`,
+			shouldError:   false,
+			expectedLines: 2,
+			validate: func(t *testing.T, lines []model.LyricLine) {
+				assert.Equal(t, model.Word{Text: "Hey", StartMs: 10000, EndMs: 10500}, lines[0].Words[0])
+				assert.Equal(t, model.Word{Text: "Hey", StartMs: 30000, EndMs: 30500}, lines[1].Words[0])
+				assert.Equal(t, model.Word{Text: "you", StartMs: 30500, EndMs: 31000}, lines[1].Words[1])
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -25,3 +26,14 @@ func (p *Parser) ParseTimestamp(timestamp string) (float64, error) {
 
 	return float64(minutes)*60 + seconds, nil
 }
+
+// toMilliseconds converts a duration in seconds to whole milliseconds
+func toMilliseconds(seconds float64) int {
+	return int(math.Round(seconds * 1000))
+}
+
+// formatTimestamp converts milliseconds to an LRC timestamp string (mm:ss.xx)
+func formatTimestamp(ms int) string {
+	centiseconds := (ms + 5) / 10
+	return fmt.Sprintf("%02d:%02d.%02d", centiseconds/6000, (centiseconds/100)%60, centiseconds%100)
+}
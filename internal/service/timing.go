@@ -0,0 +1,177 @@
+package service
+
+import "github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+
+// assignEndTimes fills EndMs and DurationMs on synced lines that lack them.
+// Each line ends where the next later line starts; the last line ends at
+// trackDurationMs when known. Lines without StartMs, and lines that already
+// have an end (such as subtitle cues), are left untouched.
+func assignEndTimes(lines []model.LyricLine, trackDurationMs int) {
+	for i := range lines {
+		if lines[i].StartMs == nil || lines[i].EndMs != nil {
+			continue
+		}
+		start := *lines[i].StartMs
+
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if lines[j].StartMs != nil && *lines[j].StartMs > start {
+				end = *lines[j].StartMs
+				break
+			}
+		}
+
+		if end < 0 && trackDurationMs > start {
+			end = trackDurationMs
+		}
+
+		if end < 0 {
+			continue
+		}
+
+		lines[i].EndMs = intPtr(end)
+		lines[i].DurationMs = intPtr(end - start)
+
+		// The last word of an enhanced LRC line without an end tag ends with the line
+		if n := len(lines[i].Words); n > 0 && lines[i].Words[n-1].EndMs == 0 {
+			lines[i].Words[n-1].EndMs = end
+		}
+	}
+}
+
+// intPtr returns a pointer to the given int
+func intPtr(v int) *int {
+	return &v
+}
+
+// collectBreaks builds instrumental breaks from break lines.
+// Consecutive break lines are merged into a single break.
+func collectBreaks(lines []model.LyricLine) []model.Break {
+	var breaks []model.Break
+
+	for i, line := range lines {
+		if !line.IsBreak || line.StartMs == nil {
+			continue
+		}
+
+		// Extend the previous break when this one directly follows it
+		if i > 0 && lines[i-1].IsBreak && len(breaks) > 0 {
+			last := &breaks[len(breaks)-1]
+			last.EndMs = line.EndMs
+			last.DurationMs = nil
+			if last.EndMs != nil {
+				last.DurationMs = intPtr(*last.EndMs - last.StartMs)
+			}
+			continue
+		}
+
+		breaks = append(breaks, model.Break{
+			LineNumber: line.LineNumber,
+			StartMs:    *line.StartMs,
+			EndMs:      line.EndMs,
+			DurationMs: line.DurationMs,
+		})
+	}
+
+	return breaks
+}
+
+// lyricLinesOnly returns the lines that carry lyrics, dropping instrumental breaks
+func lyricLinesOnly(lines []model.LyricLine) []model.LyricLine {
+	filtered := make([]model.LyricLine, 0, len(lines))
+	for _, line := range lines {
+		if !line.IsBreak {
+			filtered = append(filtered, line)
+		}
+	}
+	return filtered
+}
+
+// hookWindowMs is the length of the preview clip the hook window points to
+const hookWindowMs = 30000
+
+// rangeTimes returns the start of the first timed line and the end of the
+// last timed line numbered from startLine to endLine
+func rangeTimes(lines []model.LyricLine, startLine, endLine int) (*int, *int) {
+	var start, end *int
+
+	for _, line := range lines {
+		if line.LineNumber < startLine || line.LineNumber > endLine {
+			continue
+		}
+		if start == nil && line.StartMs != nil {
+			start = intPtr(*line.StartMs)
+		}
+		if line.EndMs != nil {
+			end = intPtr(*line.EndMs)
+		}
+	}
+
+	return start, end
+}
+
+// timeStructure adds start and end times to sections and chorus occurrences.
+// Plain lyrics have no line timings, so their ranges stay untimed.
+func timeStructure(structure *model.Structure, lines []model.LyricLine) {
+	for i := range structure.Sections {
+		section := &structure.Sections[i]
+		section.StartMs, section.EndMs = rangeTimes(lines, section.StartLine, section.EndLine)
+	}
+
+	if structure.Chorus == nil {
+		return
+	}
+
+	for i := range structure.Chorus.Instances {
+		occurrence := &structure.Chorus.Instances[i]
+		occurrence.StartMs, occurrence.EndMs = rangeTimes(lines, occurrence.StartLine, occurrence.EndLine)
+	}
+}
+
+// findHook points at the first chorus occurrence as the most likely hook.
+// For synced lyrics the window is widened to a 30-second clip from the
+// chorus start, cut short at the end of the song. Returns nil without a chorus.
+func findHook(chorus *model.Chorus, lines []model.LyricLine, trackDurationMs int) *model.Hook {
+	if chorus == nil || !chorus.Detected || len(chorus.Instances) == 0 {
+		return nil
+	}
+
+	occurrence := chorus.Instances[0]
+	hook := &model.Hook{
+		StartLine: occurrence.StartLine,
+		EndLine:   occurrence.EndLine,
+	}
+
+	if occurrence.StartMs == nil {
+		return hook
+	}
+
+	start := *occurrence.StartMs
+	end := start + hookWindowMs
+
+	songEnd := trackDurationMs
+	for _, line := range lines {
+		if line.EndMs != nil {
+			songEnd = max(songEnd, *line.EndMs)
+		}
+	}
+	if songEnd > start && end > songEnd {
+		end = songEnd
+	}
+
+	// The window covers every lyric line that starts inside it
+	for _, line := range lines {
+		if line.IsBreak || line.StartMs == nil {
+			continue
+		}
+		if *line.StartMs >= start && *line.StartMs < end && line.LineNumber > hook.EndLine {
+			hook.EndLine = line.LineNumber
+		}
+	}
+
+	hook.StartMs = intPtr(start)
+	hook.EndMs = intPtr(end)
+	hook.DurationMs = intPtr(end - start)
+
+	return hook
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseSyncedLyrics_LineTimings(t *testing.T) {
+	parser := NewParser()
+
+	lines, err := parser.ParseSyncedLyrics("[00:10.00] First\n[00:15.50] Second\n[01:05.30] Third")
+
+	assert.NoError(t, err)
+	assert.Len(t, lines, 3)
+
+	assert.Equal(t, 10000, *lines[0].StartMs)
+	assert.Equal(t, 15500, *lines[0].EndMs)
+	assert.Equal(t, 5500, *lines[0].DurationMs)
+
+	assert.Equal(t, 15500, *lines[1].StartMs)
+	assert.Equal(t, 65300, *lines[1].EndMs)
+
+	// Without the track duration the last line stays open-ended
+	assert.Equal(t, 65300, *lines[2].StartMs)
+	assert.Nil(t, lines[2].EndMs)
+	assert.Nil(t, lines[2].DurationMs)
+
+	// String timestamps are kept for older clients
+	assert.Equal(t, "01:05.30", *lines[2].Timestamp)
+}
+
+func TestAssignEndTimes(t *testing.T) {
+	tests := []struct {
+		name            string
+		starts          []int
+		trackDurationMs int
+		expectedEnds    []*int
+	}{
+		{
+			name:            "last line ends with the track",
+			starts:          []int{1000, 2000},
+			trackDurationMs: 180000,
+			expectedEnds:    []*int{intPtr(2000), intPtr(180000)},
+		},
+		{
+			name:            "track shorter than last line",
+			starts:          []int{1000, 200000},
+			trackDurationMs: 180000,
+			expectedEnds:    []*int{intPtr(200000), nil},
+		},
+		{
+			name:            "out-of-order lines end at the next later line",
+			starts:          []int{3000, 1000, 5000},
+			trackDurationMs: 0,
+			expectedEnds:    []*int{intPtr(5000), intPtr(5000), nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make([]model.LyricLine, len(tt.starts))
+			for i, start := range tt.starts {
+				lines[i].StartMs = intPtr(start)
+			}
+
+			assignEndTimes(lines, tt.trackDurationMs)
+
+			for i, expected := range tt.expectedEnds {
+				assert.Equal(t, expected, lines[i].EndMs)
+				if expected != nil {
+					assert.Equal(t, *expected-tt.starts[i], *lines[i].DurationMs)
+				}
+			}
+		})
+	}
+}
+
+func TestAssignEndTimes_ClosesLastWord(t *testing.T) {
+	lines := []model.LyricLine{
+		{
+			StartMs: intPtr(1000),
+			Words: []model.Word{
+				{Text: "Hello", StartMs: 1000, EndMs: 1500},
+				{Text: "world", StartMs: 1500},
+			},
+		},
+	}
+
+	assignEndTimes(lines, 4000)
+
+	assert.Equal(t, 4000, lines[0].Words[1].EndMs)
+}
+
+func TestAssignEndTimes_PlainLinesUntouched(t *testing.T) {
+	lines := []model.LyricLine{{LineNumber: 1, Text: "Plain"}}
+
+	assignEndTimes(lines, 180000)
+
+	assert.Nil(t, lines[0].EndMs)
+	assert.Nil(t, lines[0].DurationMs)
+}
+
+func TestCollectBreaks(t *testing.T) {
+	parser := NewParser()
+
+	lines, err := parser.ParseSyncedLyrics(`
+		[00:10.00] First line
+		[00:20.00]
+		[00:25.00]
+		[00:40.00] Second line
+		[01:00.00]
+	`)
+	assert.NoError(t, err)
+
+	breaks := collectBreaks(lines)
+
+	assert.Len(t, breaks, 2)
+	// Consecutive break lines merge into one gap
+	assert.Equal(t, 2, breaks[0].LineNumber)
+	assert.Equal(t, 20000, breaks[0].StartMs)
+	assert.Equal(t, 40000, *breaks[0].EndMs)
+	assert.Equal(t, 20000, *breaks[0].DurationMs)
+	// Trailing break is open-ended without the track duration
+	assert.Equal(t, 60000, breaks[1].StartMs)
+	assert.Nil(t, breaks[1].EndMs)
+}
+
+func TestStructureTimingAndHook(t *testing.T) {
+	parser := NewParser()
+	lines, err := parser.ParseSyncedLyrics(`[00:05.00] Verse opens the song
+[00:10.00] Sing it loud tonight
+[00:15.00] Let the whole world hear
+[00:20.00] Another verse here
+[00:45.00] Sing it loud tonight
+[00:50.00] Let the whole world hear`)
+	assert.NoError(t, err)
+	assignEndTimes(lines, 60000)
+
+	detector := NewChorusDetector()
+	keys, err := detector.lineKeys(context.Background(), lines)
+	assert.NoError(t, err)
+	chorus := detector.detectChorus(lines, keys)
+	structure := &model.Structure{
+		Chorus:   chorus,
+		Sections: detector.segmentSong(lines, chorus, keys),
+	}
+
+	timeStructure(structure, lines)
+
+	assert.Equal(t, []model.ChorusOccurrence{
+		{StartLine: 2, EndLine: 3, StartMs: intPtr(10000), EndMs: intPtr(20000)},
+		{StartLine: 5, EndLine: 6, StartMs: intPtr(45000), EndMs: intPtr(60000)},
+	}, chorus.Instances)
+
+	assert.Equal(t, model.SectionVerse, structure.Sections[0].Type)
+	assert.Equal(t, 5000, *structure.Sections[0].StartMs)
+	assert.Equal(t, 10000, *structure.Sections[0].EndMs)
+
+	hook := findHook(chorus, lines, 60000)
+
+	assert.Equal(t, &model.Hook{
+		StartLine:  2,
+		EndLine:    4,
+		StartMs:    intPtr(10000),
+		EndMs:      intPtr(40000),
+		DurationMs: intPtr(30000),
+	}, hook)
+
+	t.Run("window is cut at the end of the song", func(t *testing.T) {
+		late := &model.Chorus{Detected: true, Instances: []model.ChorusOccurrence{chorus.Instances[1]}}
+
+		hook := findHook(late, lines, 60000)
+
+		assert.Equal(t, 45000, *hook.StartMs)
+		assert.Equal(t, 60000, *hook.EndMs)
+		assert.Equal(t, 6, hook.EndLine)
+	})
+
+	t.Run("plain lyrics point at the chorus lines only", func(t *testing.T) {
+		plain := &model.Chorus{Detected: true, Instances: []model.ChorusOccurrence{{StartLine: 3, EndLine: 4}}}
+
+		hook := findHook(plain, nil, 0)
+
+		assert.Equal(t, &model.Hook{StartLine: 3, EndLine: 4}, hook)
+	})
+
+	t.Run("no chorus, no hook", func(t *testing.T) {
+		assert.Nil(t, findHook(&model.Chorus{Detected: false}, lines, 60000))
+	})
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer names reported in the response
+const (
+	TokenizerWhitespace = "whitespace"
+	TokenizerUnicode    = "unicode"
+)
+
+// Tokenizer splits lyric text into words for counting and statistics
+type Tokenizer interface {
+	// Name identifies the tokenizer in API responses
+	Name() string
+
+	// Tokenize returns the words in text with surrounding punctuation removed
+	Tokenize(text string) []string
+}
+
+// WhitespaceTokenizer splits on whitespace and strips surrounding punctuation.
+// It suits space-delimited scripts such as Latin, Cyrillic or Hangul.
+type WhitespaceTokenizer struct{}
+
+// NewWhitespaceTokenizer creates a new whitespace tokenizer
+func NewWhitespaceTokenizer() *WhitespaceTokenizer {
+	return &WhitespaceTokenizer{}
+}
+
+// Name implements Tokenizer
+func (t *WhitespaceTokenizer) Name() string {
+	return TokenizerWhitespace
+}
+
+// Tokenize implements Tokenizer
+func (t *WhitespaceTokenizer) Tokenize(text string) []string {
+	fields := strings.Fields(text)
+	words := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// UnicodeTokenizer segments text by script. Han, Hiragana and Katakana are
+// split per character and Thai per character cluster, since those scripts
+// do not separate words with spaces. Other scripts are split on whitespace
+// and punctuation, keeping in-word apostrophes ("don't", "rock 'n' roll").
+type UnicodeTokenizer struct{}
+
+// NewUnicodeTokenizer creates a new script-aware tokenizer
+func NewUnicodeTokenizer() *UnicodeTokenizer {
+	return &UnicodeTokenizer{}
+}
+
+// Name implements Tokenizer
+func (t *UnicodeTokenizer) Name() string {
+	return TokenizerUnicode
+}
+
+// Tokenize implements Tokenizer
+func (t *UnicodeTokenizer) Tokenize(text string) []string {
+	var words []string
+	var current []rune
+	thaiCluster := false
+
+	flush := func() {
+		word := strings.TrimRight(string(current), "'’")
+		if word != "" {
+			words = append(words, word)
+		}
+		current = current[:0]
+		thaiCluster = false
+	}
+
+	for _, r := range text {
+		switch {
+		case isIdeographic(r):
+			flush()
+			words = append(words, string(r))
+
+		case unicode.Is(unicode.Thai, r):
+			// Combining vowels and tone marks stay with their consonant, as
+			// does a consonant that follows a leading vowel (เ, แ, โ, ใ, ไ)
+			joins := thaiCluster && (unicode.Is(unicode.Mn, r) || isThaiFollowingVowel(r) || endsWithThaiLeadingVowel(current))
+			if !joins {
+				flush()
+			}
+			current = append(current, r)
+			thaiCluster = true
+
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			if thaiCluster {
+				flush()
+			}
+			current = append(current, r)
+
+		case (r == '\'' || r == '’') && len(current) > 0 && !thaiCluster:
+			current = append(current, r)
+
+		default:
+			flush()
+		}
+	}
+
+	flush()
+
+	return words
+}
+
+// isIdeographic reports whether r belongs to a script written without spaces
+// where each character is treated as a word
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isThaiFollowingVowel reports whether r is a Thai vowel written after its consonant
+func isThaiFollowingVowel(r rune) bool {
+	return r == 'ะ' || r == 'า' || r == 'ำ' || r == 'ๆ'
+}
+
+// endsWithThaiLeadingVowel reports whether the cluster so far is a lone leading vowel
+func endsWithThaiLeadingVowel(cluster []rune) bool {
+	if len(cluster) == 0 {
+		return false
+	}
+	last := cluster[len(cluster)-1]
+	return last >= 'เ' && last <= 'ไ'
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnicodeTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"latin with punctuation", "Hello, world!", []string{"Hello", "world"}},
+		{"apostrophes kept inside words", "C'est la vie & rock 'n' roll", []string{"C'est", "la", "vie", "rock", "n", "roll"}},
+		{"curly apostrophe", "Don’t stop", []string{"Don’t", "stop"}},
+		{"numbers", "100% awesome!!!", []string{"100", "awesome"}},
+		{"accented latin", "Despacito, quiero respirar tu cuello despacito", []string{"Despacito", "quiero", "respirar", "tu", "cuello", "despacito"}},
+		{"hangul stays space-delimited", "사랑해 너를", []string{"사랑해", "너를"}},
+		{"japanese per character", "君の名は", []string{"君", "の", "名", "は"}},
+		{"chinese per character", "我爱你", []string{"我", "爱", "你"}},
+		{"mixed scripts", "Love 愛してる", []string{"Love", "愛", "し", "て", "る"}},
+		{"thai character clusters", "ไม่รู้", []string{"ไม่", "รู้"}},
+		{"only punctuation", "... !!!", nil},
+	}
+
+	tokenizer := NewUnicodeTokenizer()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tokenizer.Tokenize(tt.input))
+		})
+	}
+}
+
+func TestWhitespaceTokenizer_Tokenize(t *testing.T) {
+	tokenizer := NewWhitespaceTokenizer()
+
+	assert.Equal(t, []string{"Hello", "world"}, tokenizer.Tokenize("Hello,  world!"))
+	// Without script awareness a CJK line is a single word
+	assert.Equal(t, []string{"我爱你"}, tokenizer.Tokenize("我爱你"))
+	assert.Equal(t, TokenizerWhitespace, tokenizer.Name())
+}
+
+func TestParser_WordCountUsesTokenizer(t *testing.T) {
+	lines, err := NewParser().ParsePlainLyrics("我爱你\n君の名は")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, lines[0].WordCount)
+	assert.Equal(t, 4, lines[1].WordCount)
+
+	lines, err = NewParserWithTokenizer(NewWhitespaceTokenizer()).ParsePlainLyrics("我爱你")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, lines[0].WordCount)
+}
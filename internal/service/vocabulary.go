@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+)
+
+// mtldThreshold is the type-token ratio at which MTLD closes a factor
+const mtldThreshold = 0.72
+
+// VocabularyAnalyzer measures lexical diversity and readability. Words are
+// split, stemmed and checked against stopwords according to the song's
+// language.
+type VocabularyAnalyzer struct {
+	syllables *SyllableCounter
+}
+
+// NewVocabularyAnalyzer creates a vocabulary analyzer using the bundled pronunciation dictionary
+func NewVocabularyAnalyzer() *VocabularyAnalyzer {
+	return &VocabularyAnalyzer{
+		syllables: NewSyllableCounter(),
+	}
+}
+
+// Name implements Analyzer
+func (a *VocabularyAnalyzer) Name() string {
+	return AnalyzerVocabulary
+}
+
+// Analyze implements Analyzer
+func (a *VocabularyAnalyzer) Analyze(ctx context.Context, input *AnalysisInput, response *model.SongAnalysisResponse) error {
+	support := supportFor(input.Language)
+	var words []string
+	sentences, syllables := 0, 0
+
+	for _, line := range lyricLinesOnly(input.Lines) {
+		lineWords := support.tokenizer.Tokenize(line.Text)
+		if len(lineWords) == 0 {
+			continue
+		}
+		sentences++
+
+		for _, word := range lineWords {
+			word = strings.ToLower(word)
+			words = append(words, word)
+			syllables += a.syllables.CountInLanguage(word, lineLanguage(line, input.Language))
+		}
+	}
+
+	vocabulary := &model.Vocabulary{TotalWords: len(words)}
+	if len(words) == 0 {
+		response.Vocabulary = vocabulary
+		return nil
+	}
+
+	counts := make(map[string]int)
+	stems := make(map[string]struct{})
+	contentWords := 0
+	for _, word := range words {
+		counts[word]++
+		stems[support.stem(strings.Trim(word, "'’"))] = struct{}{}
+		if _, ok := support.stopwords[word]; !ok {
+			contentWords++
+		}
+	}
+	for _, count := range counts {
+		if count == 1 {
+			vocabulary.HapaxLegomena++
+		}
+	}
+
+	vocabulary.UniqueWords = len(counts)
+	vocabulary.StemmedUniqueWords = len(stems)
+	vocabulary.TypeTokenRatio = round2(float64(len(counts)) / float64(len(words)))
+	vocabulary.MTLD = round2(mtld(words))
+
+	// Lexical density needs a stopword list for the song's language
+	if support.stopwords != nil {
+		vocabulary.LexicalDensity = round2(float64(contentWords) / float64(len(words)))
+	}
+
+	wordsPerSentence := float64(len(words)) / float64(sentences)
+	syllablesPerWord := float64(syllables) / float64(len(words))
+	vocabulary.FleschKincaidGrade = round2(0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59)
+	vocabulary.FleschReadingEase = round2(206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord)
+
+	response.Vocabulary = vocabulary
+	return nil
+}
+
+// mtld is the measure of textual lexical diversity: the mean number of
+// words it takes for the running type-token ratio to fall to the
+// threshold, averaged over forward and backward passes
+func mtld(words []string) float64 {
+	reversed := make([]string, len(words))
+	for i, word := range words {
+		reversed[len(words)-1-i] = word
+	}
+	return (mtldPass(words) + mtldPass(reversed)) / 2
+}
+
+// mtldPass counts factors in one direction; the unfinished remainder counts
+// as the fraction of a factor its ratio has covered
+func mtldPass(words []string) float64 {
+	factors := 0.0
+	types := make(map[string]struct{})
+	tokens := 0
+
+	for _, word := range words {
+		types[word] = struct{}{}
+		tokens++
+
+		if float64(len(types))/float64(tokens) <= mtldThreshold {
+			factors++
+			types = make(map[string]struct{})
+			tokens = 0
+		}
+	}
+
+	if tokens > 0 {
+		ratio := float64(len(types)) / float64(tokens)
+		factors += (1 - ratio) / (1 - mtldThreshold)
+	}
+
+	// Text that never repeats a word has no factors; its length is the best estimate
+	if factors == 0 {
+		return float64(len(words))
+	}
+	return float64(len(words)) / factors
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mgomez-halley-code/lyrics-analyzer.git/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVocabularyAnalyzer_Analyze(t *testing.T) {
+	input := &AnalysisInput{Lines: songLines("I love you", "Loving you is easy", "", "I love you"), Language: "en"}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewVocabularyAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	assert.Equal(t, &model.Vocabulary{
+		TotalWords:         10,
+		UniqueWords:        6,
+		TypeTokenRatio:     0.6,
+		MTLD:               10,
+		HapaxLegomena:      3, // loving, is, easy
+		StemmedUniqueWords: 5, // loving shares the stem of love
+		LexicalDensity:     0.4,
+		FleschKincaidGrade: -0.13,
+		FleschReadingEase:  101.93,
+	}, response.Vocabulary)
+}
+
+func TestVocabularyAnalyzer_Analyze_Language(t *testing.T) {
+	lines := songLines("Quiero bailar contigo", "Bailando toda la noche")
+
+	tests := []struct {
+		name     string
+		language string
+		stemmed  int
+		density  float64
+	}{
+		{"spanish stopwords, no stemming", "es", 7, 0.71},
+		{"unknown language", "", 7, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &AnalysisInput{Lines: lines, Language: tt.language}
+			response := &model.SongAnalysisResponse{}
+
+			err := NewVocabularyAnalyzer().Analyze(context.Background(), input, response)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.stemmed, response.Vocabulary.StemmedUniqueWords)
+			assert.Equal(t, tt.density, response.Vocabulary.LexicalDensity)
+		})
+	}
+}
+
+func TestVocabularyAnalyzer_Analyze_Empty(t *testing.T) {
+	input := &AnalysisInput{Lines: songLines("", "...")}
+	response := &model.SongAnalysisResponse{}
+
+	err := NewVocabularyAnalyzer().Analyze(context.Background(), input, response)
+
+	require.NoError(t, err)
+	assert.Equal(t, &model.Vocabulary{}, response.Vocabulary)
+}
+
+func TestMTLD(t *testing.T) {
+	// Repeating one word closes a factor every second word
+	repetitive := []string{"la", "la", "la", "la", "la", "la", "la", "la"}
+	assert.Equal(t, 2.0, mtld(repetitive))
+
+	// Words that never repeat count as one long factor
+	varied := []string{"every", "word", "here", "is", "new"}
+	assert.Equal(t, 5.0, mtld(varied))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -34,7 +35,8 @@ func TestIntegration_SongAnalyze(t *testing.T) {
 	mockClient := &mockLyricsClient{}
 	parser := service.NewParser()
 	chorusDetector := service.NewChorusDetector()
-	svc := service.NewLyricsService(mockClient, parser, chorusDetector)
+	statsCalc := service.NewStatisticsCalculator()
+	svc := service.NewLyricsService(mockClient, parser, service.NewStructureAnalyzer(chorusDetector), service.NewStatisticsAnalyzer(statsCalc))
 
 	// Build router and test server
 	router := server.NewRouter(svc)
@@ -78,7 +80,8 @@ func TestIntegration_Instrumental(t *testing.T) {
 	mock := &mockInstrumentalClient{}
 	parser := service.NewParser()
 	chorusDetector := service.NewChorusDetector()
-	svc := service.NewLyricsService(mock, parser, chorusDetector)
+	statsCalc := service.NewStatisticsCalculator()
+	svc := service.NewLyricsService(mock, parser, service.NewStructureAnalyzer(chorusDetector), service.NewStatisticsAnalyzer(statsCalc))
 
 	router := server.NewRouter(svc)
 	ts := httptest.NewServer(router)
@@ -121,7 +124,8 @@ func TestIntegration_UpstreamError(t *testing.T) {
 	mock := &mockErrorClient{}
 	parser := service.NewParser()
 	chorusDetector := service.NewChorusDetector()
-	svc := service.NewLyricsService(mock, parser, chorusDetector)
+	statsCalc := service.NewStatisticsCalculator()
+	svc := service.NewLyricsService(mock, parser, service.NewStructureAnalyzer(chorusDetector), service.NewStatisticsAnalyzer(statsCalc))
 
 	router := server.NewRouter(svc)
 	ts := httptest.NewServer(router)
@@ -144,3 +148,128 @@ type mockErrorClient struct{}
 func (m *mockErrorClient) GetLyrics(ctx context.Context, track, artist string) (*model.LyricsSourceData, error) {
 	return nil, fmt.Errorf("upstream failure")
 }
+
+func TestIntegration_SongLyricsExport(t *testing.T) {
+	mock := &mockSyncedClient{}
+	parser := service.NewParser()
+	chorusDetector := service.NewChorusDetector()
+	statsCalc := service.NewStatisticsCalculator()
+	svc := service.NewLyricsService(mock, parser, service.NewStructureAnalyzer(chorusDetector), service.NewStatisticsAnalyzer(statsCalc))
+
+	router := server.NewRouter(svc)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/song/lyrics?track=Synced&artist=Artist&format=srt")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-subrip; charset=utf-8" {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	expected := "1\n00:00:10,000 --> 00:00:15,000\nHello world\n\n2\n00:00:15,000 --> 00:03:00,000\nGoodbye world\n"
+	if string(body) != expected {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+
+	resp, err = http.Get(ts.URL + "/api/song/lyrics?track=Synced&artist=Artist&format=docx")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported format, got %d", resp.StatusCode)
+	}
+}
+
+// mockSyncedClient returns synced LRC lyrics
+type mockSyncedClient struct{}
+
+func (m *mockSyncedClient) GetLyrics(ctx context.Context, track, artist string) (*model.LyricsSourceData, error) {
+	return &model.LyricsSourceData{
+		TrackID:      7,
+		TrackName:    track,
+		ArtistName:   artist,
+		Duration:     180,
+		SyncedLyrics: "[00:10.00] Hello world\n[00:15.00] Goodbye world",
+	}, nil
+}
+
+func TestIntegration_SongAnalyzeIncludeExclude(t *testing.T) {
+	mockClient := &mockLyricsClient{}
+	parser := service.NewParser()
+	chorusDetector := service.NewChorusDetector()
+	statsCalc := service.NewStatisticsCalculator()
+	svc := service.NewLyricsService(mockClient, parser, service.NewStructureAnalyzer(chorusDetector), service.NewStatisticsAnalyzer(statsCalc))
+
+	router := server.NewRouter(svc)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/song/analyze?track=MyTrack&artist=MyArtist&include=lyrics,statistics&exclude=lines")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var result model.SongAnalysisResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if result.Lyrics == nil || result.Lyrics.TotalLines != 3 || result.Lyrics.Lines != nil {
+		t.Fatalf("expected lyrics summary without lines, got %+v", result.Lyrics)
+	}
+
+	if result.Structure != nil {
+		t.Fatalf("expected structure to be skipped")
+	}
+
+	if result.Statistics == nil {
+		t.Fatalf("expected statistics in response")
+	}
+
+	bad, err := http.Get(ts.URL + "/api/song/analyze?track=MyTrack&artist=MyArtist&include=mood")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer bad.Body.Close()
+
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown section, got %d", bad.StatusCode)
+	}
+
+	badLanguage, err := http.Get(ts.URL + "/api/song/analyze?track=MyTrack&artist=MyArtist&language=xx")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer badLanguage.Body.Close()
+
+	if badLanguage.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown language, got %d", badLanguage.StatusCode)
+	}
+
+	// No configured analyzer can mask explicit words
+	badClean, err := http.Get(ts.URL + "/api/song/analyze?track=MyTrack&artist=MyArtist&clean=true")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer badClean.Body.Close()
+
+	if badClean.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unavailable clean edit, got %d", badClean.StatusCode)
+	}
+}